@@ -1,10 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/dlclark/regexp2"
@@ -27,7 +36,31 @@ func (m perlRegexpMatcher) MatchString(s string) bool {
 	return err == nil && ok
 }
 
+// compileMatcher prefers Go regexp for speed when possible, but falls back to a
+// Perl-like engine to support lookaheads such as (?!...).
+func compileMatcher(expr string) (treego.NameMatcher, error) {
+	re, goErr := regexp.Compile(expr)
+	if goErr == nil {
+		return goRegexpMatcher{re: re}, nil
+	}
+	perl, err := regexp2.Compile(expr, 0)
+	if err != nil {
+		return nil, goErr
+	}
+	perl.MatchTimeout = regexp2.DefaultMatchTimeout
+	// If user anchored to the whole name (e.g. ^...$), keep behavior.
+	// Otherwise behavior stays "match anywhere" like Go's regexp does.
+	return perlRegexpMatcher{re: perl}, nil
+}
+
 func main() {
+	os.Exit(run())
+}
+
+// run is the treego command. It returns the exit status instead of calling
+// os.Exit, so deferred cleanup such as the --max-output-bytes notice runs on
+// every path.
+func run() int {
 	app := kingpin.New("treego", "Print directory tree and search files").
 		Version("v1.0").
 		Author("Mark Wayne Menorca")
@@ -38,69 +71,998 @@ func main() {
 	GitHub: https://github.com/marcuwynu23
 
 	Usage:
-	treego <path> [--search <query>] [--regex <pattern>] [--exclude <pattern>...] [--dirs-only] [--version]
+	treego <path> [flags]
 
 	Flags:
 	--search, -s       Search string (prints full path)
+	--search-counts    With --search, print each directory with its number of matching descendants
+	--rel-cwd          Print search and grep paths relative to the current directory
+	--git-relative     Print search and grep paths relative to the enclosing git repository root
+	--grep, -g         Content regex (prints path and matching lines); combined with --search both must match
+	--any              With --search and --grep, match either condition instead of both
+	--content-ext      Read file contents (grep, hashes, signatures, symbols, previews) only for these extensions (repeatable or comma-separated)
+	--content-max-size  Read file contents only for files up to this size, e.g. 10MiB; larger files are shown but not read
 	--regex, -r        Regex filter
 	--exclude, -x      Exclude pattern (repeatable). Supports exact name (node_modules), glob (*.pem), or regex (re:<expr>)
+	--ignore-common    Exclude common noise: VCS dirs, node_modules, build output, caches, lock files
+	--ext, -e          Only include files with these extensions (repeatable or comma-separated, e.g. go,md)
+	--no-hidden-dirs   Skip dot-directories (.git, .cache) and their contents
+	--no-hidden-files  Skip dotfiles (.env, .gitignore)
+	--follow-symlinks, -L  Descend into symlinked directories
+	--max-symlink-depth    With --follow-symlinks, give up on symlink chains longer than this (default 40)
+	--find             Show only entries whose relative path matches a recursive glob such as "**/*_test.go" (repeatable)
+	--path-to          Show only the directory chains leading to entries matching this pattern (repeatable; --exclude syntax)
+	--paths-from       Show only the paths listed in this file (one per line; --paths-from=- reads stdin) and their parent directories
+	--modified-within  Keep files modified within this long before now (e.g. 2h, 7d, 2w)
+	--where            Keep entries matching an expression, e.g. "size > 1MB && ext == go"
 	--dirs-only, -d    Show only directories
+	--ext-group        Count related extensions as one type in stats, ext sort and collapsing, e.g. "jpg=image,jpeg=image" (repeatable)
+	--fold-identical   Print directories with the same layout as an earlier one as "≡ same as <path>"
+	--collapse-ext     Show at most N files per extension in each directory, then "... and M more .ext"
+	--breadcrumbs      Print a "── path ──" header before each directory at this depth (1 = top level)
+	--sort             Sort entries by name, natural (file2 before file10), size (largest first), mtime (newest first), ext (extension, then name) or hot (newest change in the subtree first)
+	--hot              List branches holding the most recent changes first (same as --sort hot)
+	--[no-]sort-ignore-case  Sort names case-insensitively (default on); --no-sort-ignore-case uses byte order
+	--[no-]deterministic  Canonical order: dirs first, then byte-order names (default for machine formats)
+	--fromfile         Read the tree from a JSON file written by --format json instead of scanning <path>
+	--format, -f       Output format: tree, horizontal, json, tree-json, ndjson, yaml, html, dot, plantuml, csv, markdown, rst, sql, manifest (default tree)
+	--errors-json      Report scan errors on stderr as JSON Lines (path, op, message)
+	--io               Concurrency preset for the storage: auto (detect), ssd, hdd or network (default auto)
+	--workers          Number of directories read concurrently, overriding --io
+	--timeout          Stop scanning after this duration (e.g. 30s) and show the partial tree
+	--tar              Stream the selected entries into a tar archive at this path (- for stdout)
+	--tar-log          With --tar, also print the tree to stderr
+	--with-ids         Add a stable per-entry ID (hash of the relative path) to json/ndjson/yaml output
+	--show-owner       Show each entry's owner as user:group
+	--atime            Show each entry's last access time
+	--ctime            Show each entry's inode change time (metadata changes)
+	--time-format      Go time layout for --atime and --ctime (default 2006-01-02 15:04)
+	--signatures       Label files with the format recognized from their magic bytes (PNG, ELF, ZIP, ...)
+	--git-authors      Annotate tracked files with the author and date of their last commit
+	--readme-preview   Show the first lines of each directory's README under its name
+	--preview          Show the first N lines of each text file, dimmed, under its name
+	--symbols          Outline top-level declarations (Go funcs, methods and types) under each source file
+	--rank-recent      Annotate files with their rank by modification time across the tree (#1 = newest)
+	--rank-top         With --rank-recent, only annotate the N most recent files
+	--mine             Show only files owned by the current user, with their parent directories
+	--size             Show human-readable file sizes
+	--columns          Print these columns as an aligned table, in order, e.g. size,mtime,name (size, perms, mtime, atime, ctime, owner, name)
+	--running-total    Show each file's size with the running total of all sizes printed so far
+	--du               Show each directory's total size, like du
+	--counts-recursive Show how many files and directories each directory holds at any depth
+	--si               Use 1000-based size units (KB, MB) instead of 1024-based (KiB, MiB)
+	--output, -o       Write the output to this file instead of stdout
+	--max-output-bytes Stop after writing N bytes of output, with a truncation notice on stderr
+	--force-color-in-file  With --color=always, keep escape codes even when writing to a file
+	--summary-only     Print only directory, file and byte totals (low memory on huge trees)
+	--inode-summary    Print total bytes and inodes (hard links counted once) below the tree
+	--box              Frame the tree in a box titled with the root path
+	--grouped          Print each top-level directory as its own section with its totals, then a grand total
+	--composition      Print a one-line bar of bytes by file type above the tree
+	--zebra            Shade every other line with a subtle background color (needs colors)
+	--heatmap          Color file names from green (small) to red (large), with a legend
+	--color            When to use colors, dim guides, icons and hyperlinks: auto, always, never (default auto)
+	--icons            Prefix entries with file/folder icons (terminal output only unless --color=always)
+	--hyperlinks       Make entry names clickable terminal hyperlinks (terminal output only unless --color=always)
+	--ascii            Draw the tree with plain ASCII (|--) instead of box-drawing characters
+	--max-depth        Print entries at most this many levels below the root
+	--progressive      Print each top-level entry as soon as it is scanned instead of after the whole scan (tree format)
+	--spinner          On a terminal, show an activity indicator on stderr while a slow scan runs
+	--fit-screen       On a terminal, hide deeper levels until the tree fits the screen height
+	--reveal           On a terminal, draw the tree one level at a time with this delay between levels (e.g. 500ms)
+	--for-commit       Plain ASCII tree in a code fence, uncolored and at most 72 columns wide, for commit messages
+	--[no-]abs-root    Show the absolute path of the root as the tree header (default on)
+	--delta            Print only what changed since the snapshot stored in this state file, then update it
+	--detect-renames   With --delta, report files that moved or changed name with the same content as renames
+	--size-histogram   Print a bar chart of file counts by size range (0-1K, 1K-10K, ...)
+	--submodules       List the git submodules under <path> with their URLs
+	--level            Print only the entries exactly this many levels below the root, as a flat list
+	--extremes         Report the deepest and shallowest file and the average file depth
+	--broken-links     List only symlinks whose target does not exist (exits non-zero if any)
+	--case-collisions  Report names in the same directory that differ only by case
+	--no-ext-warn      List files without an extension, with their parent directories (exits non-zero if any)
+	--check-path-length  Report paths longer than the Windows MAX_PATH limit of 260 characters (exits non-zero if any)
+	--git-large-files  Warn about committable files over GitHub's 50 MiB warning size (exits non-zero if any exceed the 100 MiB hard limit)
+	--git-large-warn   With --git-large-files, the warning threshold (default 50MiB)
+	--git-large-limit  With --git-large-files, the hard limit (default 100MiB)
+	--tree-hash        Print one Merkle hash of the tree's structure and file contents
+	--sitemap          Treat <path> as a web root served at this base URL and print a sitemap.xml of its HTML files
+	--sitemap-ext      With --sitemap, list files with these extensions instead of html and htm (repeatable or comma-separated)
+	--verify           Check the tree against a sha256 manifest; exits non-zero on discrepancies
 	--version          Show version
 	`)
 
 	path := app.Arg("path", "root directory to scan").Required().String()
 	search := app.Flag("search", "search string (prints full path)").Short('s').String()
+	searchCounts := app.Flag("search-counts", "with --search, print each directory with its number of matching descendants").Bool()
+	relCwd := app.Flag("rel-cwd", "print search and grep paths relative to the current directory").Bool()
+	gitRelative := app.Flag("git-relative", "print search and grep paths relative to the enclosing git repository root").Bool()
+	grep := app.Flag("grep", "content regex (prints path and matching lines)").Short('g').String()
+	matchAny := app.Flag("any", "with --search and --grep, match either condition instead of both").Bool()
+	contentExts := app.Flag("content-ext", "read file contents (grep, hashes, signatures, symbols, previews) only for these extensions (repeatable or comma-separated)").Strings()
+	contentMaxSize := app.Flag("content-max-size", "read file contents only for files up to this size, e.g. 10MiB; larger files are shown but not read").PlaceHolder("SIZE").String()
 	regexStr := app.Flag("regex", "regex filter").Short('r').String()
 	excludePatterns := app.Flag("exclude", "exclude pattern (repeatable). supports exact name, glob, or regex re:<expr>").Short('x').Strings()
+	ignoreCommon := app.Flag("ignore-common", "exclude common noise: VCS dirs, node_modules, build output, caches, lock files").Bool()
+	extensions := app.Flag("ext", "only include files with these extensions (repeatable or comma-separated)").Short('e').Strings()
+	noHiddenDirs := app.Flag("no-hidden-dirs", "skip dot-directories such as .git and .cache").Bool()
+	noHiddenFiles := app.Flag("no-hidden-files", "skip dotfiles such as .env").Bool()
+	followSymlinks := app.Flag("follow-symlinks", "descend into symlinked directories").Short('L').Bool()
+	ioProfile := app.Flag("io", "concurrency preset for the storage: auto (detect), ssd, hdd or network").Default("auto").Enum("auto", "ssd", "hdd", "network")
+	workers := app.Flag("workers", "number of directories read concurrently, overriding --io").PlaceHolder("N").Int()
+	maxSymlinkDepth := app.Flag("max-symlink-depth", "with --follow-symlinks, give up on symlink chains longer than this").Default(strconv.Itoa(treego.DefaultMaxSymlinkDepth)).Int()
+	findGlobs := app.Flag("find", "show only entries whose relative path matches a recursive glob such as \"**/*_test.go\" (repeatable)").PlaceHolder("GLOB").Strings()
+	pathToPatterns := app.Flag("path-to", "show only the directory chains leading to matching entries (repeatable; --exclude syntax)").Strings()
+	pathsFrom := app.Flag("paths-from", "show only the paths listed in this file (one per line; --paths-from=- reads stdin) and their parent directories").PlaceHolder("FILE").String()
+	modifiedWithin := app.Flag("modified-within", "keep files modified within this long before now (e.g. 2h, 7d, 2w)").PlaceHolder("DURATION").String()
+	where := app.Flag("where", "keep entries matching an expression, e.g. \"size > 1MB && ext == go\"").PlaceHolder("EXPR").String()
 	dirsOnly := app.Flag("dirs-only", "show only directories").Short('d').Bool()
+	extGroupSpecs := app.Flag("ext-group", "count related extensions as one type, e.g. \"jpg=image,jpeg=image\" (repeatable)").PlaceHolder("EXT=GROUP").Strings()
+	foldIdentical := app.Flag("fold-identical", "print directories with the same layout as an earlier one as \"≡ same as <path>\"").Bool()
+	collapseExt := app.Flag("collapse-ext", "show at most N files per extension in each directory, then \"... and M more\"").PlaceHolder("N").Int()
+	breadcrumbs := app.Flag("breadcrumbs", "print a \"── path ──\" header before each directory at this depth (1 = top level)").PlaceHolder("DEPTH").Int()
+	var sortSet, deterministicSet bool
+	sortMode := app.Flag("sort", "sort entries by name, natural, size, mtime, ext or hot").Default("name").IsSetByUser(&sortSet).Enum("name", "natural", "size", "mtime", "ext", "hot")
+	hot := app.Flag("hot", "list branches with the most recent changes first (same as --sort hot)").Bool()
+	deterministic := app.Flag("deterministic", "canonical order (dirs first, byte-order names) independent of filesystem; default for json, ndjson, yaml, csv, manifest and sql unless --sort is given").IsSetByUser(&deterministicSet).Bool()
+	sortIgnoreCase := app.Flag("sort-ignore-case", "sort names case-insensitively (use --no-sort-ignore-case for byte order)").Default("true").Bool()
+	signatures := app.Flag("signatures", "label files with the format recognized from their magic bytes (PNG, ELF, ZIP, ...)").Bool()
+	gitAuthors := app.Flag("git-authors", "annotate tracked files with the author and date of their last commit").Bool()
+	preview := app.Flag("preview", "show the first N lines of each text file, dimmed, under its name").PlaceHolder("N").Int()
+	readmePreview := app.Flag("readme-preview", "show the first lines of each directory's README under its name").Bool()
+	symbols := app.Flag("symbols", "outline top-level declarations (Go funcs, methods and types) under each source file").Bool()
+	rankRecent := app.Flag("rank-recent", "annotate files with their rank by modification time across the tree (#1 = newest)").Bool()
+	rankTop := app.Flag("rank-top", "with --rank-recent, only annotate the N most recent files").PlaceHolder("N").Int()
+	mine := app.Flag("mine", "show only files owned by the current user").Bool()
+	showOwner := app.Flag("show-owner", "show each entry's owner as user:group").Bool()
+	showATime := app.Flag("atime", "show each entry's last access time").Bool()
+	showCTime := app.Flag("ctime", "show each entry's inode change time").Bool()
+	timeFormat := app.Flag("time-format", "Go time layout for --atime and --ctime").Default(treego.DefaultTimeFormat).String()
+	du := app.Flag("du", "show each directory's total size, like du").Bool()
+	countsRecursive := app.Flag("counts-recursive", "show how many files and directories each directory holds at any depth").Bool()
+	showSize := app.Flag("size", "show human-readable file sizes").Bool()
+	columnSpec := app.Flag("columns", "print these columns as an aligned table, in order, e.g. size,mtime,name").PlaceHolder("COLS").String()
+	runningTotal := app.Flag("running-total", "show each file's size with the running total of all sizes printed so far").Bool()
+	si := app.Flag("si", "use 1000-based size units (KB, MB) instead of 1024-based (KiB, MiB)").Bool()
+	maxOutputBytes := app.Flag("max-output-bytes", "stop after writing this many bytes of output, with a notice on stderr").PlaceHolder("N").Int64()
+	output := app.Flag("output", "write the output to this file instead of stdout").Short('o').PlaceHolder("FILE").String()
+	forceColorInFile := app.Flag("force-color-in-file", "with --color=always, keep escape codes even when writing to a file").Bool()
+	summaryOnly := app.Flag("summary-only", "print only directory, file and byte totals; file entries are not kept in memory").Bool()
+	inodeSummary := app.Flag("inode-summary", "print total bytes and inodes (hard links counted once) below the tree").Bool()
+	grouped := app.Flag("grouped", "print each top-level directory as its own section with its totals, then a grand total").Bool()
+	box := app.Flag("box", "frame the tree in a box titled with the root path").Bool()
+	composition := app.Flag("composition", "print a one-line bar of bytes by file type above the tree").Bool()
+	zebra := app.Flag("zebra", "shade every other line with a subtle background color").Bool()
+	heatmap := app.Flag("heatmap", "color file names from green (small) to red (large), with a legend").Bool()
+	colorMode := app.Flag("color", "when to use colors, dim guides, icons and hyperlinks").Default("auto").Enum("auto", "always", "never")
+	icons := app.Flag("icons", "prefix entries with file/folder icons").Bool()
+	hyperlinks := app.Flag("hyperlinks", "make entry names clickable terminal hyperlinks").Bool()
+	ascii := app.Flag("ascii", "draw the tree with plain ASCII instead of box-drawing characters").Bool()
+	maxDepth := app.Flag("max-depth", "print entries at most this many levels below the root").PlaceHolder("N").Int()
+	progressive := app.Flag("progressive", "print each top-level entry as soon as it is scanned instead of after the whole scan (tree format)").Bool()
+	spinner := app.Flag("spinner", "on a terminal, show an activity indicator on stderr while a slow scan runs").Bool()
+	fitScreen := app.Flag("fit-screen", "on a terminal, hide deeper levels until the tree fits the screen height").Bool()
+	reveal := app.Flag("reveal", "on a terminal, draw the tree one level at a time with this delay between levels (e.g. 500ms)").PlaceHolder("DELAY").Duration()
+	forCommit := app.Flag("for-commit", "plain ASCII tree in a code fence, ready to paste into a commit message").Bool()
+	absRoot := app.Flag("abs-root", "show the absolute path of the root as the tree header (use --no-abs-root for the short name)").Default("true").Bool()
+	fromFile := app.Flag("fromfile", "read the tree from a JSON file written by --format json instead of scanning path").Bool()
+	format := app.Flag("format", "output format (tree, horizontal, json, tree-json, ndjson, yaml, html, dot, plantuml, csv, markdown, rst, sql, manifest)").Short('f').Default(treego.DefaultFormat).String()
+	delta := app.Flag("delta", "print only changes since the snapshot stored in this state file, then update it").PlaceHolder("STATE").String()
+	detectRenames := app.Flag("detect-renames", "with --delta, report files that moved or changed name with the same content as renames").Bool()
+	sizeHistogram := app.Flag("size-histogram", "print a bar chart of file counts by size range (0-1K, 1K-10K, ...)").Bool()
+	submodules := app.Flag("submodules", "list the git submodules under path with their URLs").Bool()
+	level := app.Flag("level", "print only the entries exactly this many levels below the root, as a flat list").PlaceHolder("N").Int()
+	extremes := app.Flag("extremes", "report the deepest and shallowest file and the average file depth").Bool()
+	brokenLinks := app.Flag("broken-links", "list only symlinks whose target does not exist (exits non-zero if any)").Bool()
+	caseCollisions := app.Flag("case-collisions", "report names in the same directory that differ only by case").Bool()
+	noExtWarn := app.Flag("no-ext-warn", "list files without an extension, with their parent directories (exits non-zero if any)").Bool()
+	checkPathLength := app.Flag("check-path-length", "report paths longer than the Windows MAX_PATH limit of 260 characters (exits non-zero if any)").Bool()
+	gitLargeFiles := app.Flag("git-large-files", "warn about committable files over GitHub's 50 MiB warning size (exits non-zero if any exceed the 100 MiB hard limit)").Bool()
+	gitLargeWarn := app.Flag("git-large-warn", "with --git-large-files, the warning threshold").Default("50MiB").PlaceHolder("SIZE").String()
+	gitLargeLimit := app.Flag("git-large-limit", "with --git-large-files, the hard limit").Default("100MiB").PlaceHolder("SIZE").String()
+	verify := app.Flag("verify", "check the tree against a sha256 manifest (exits non-zero on discrepancies)").PlaceHolder("MANIFEST").String()
+	errorsJSON := app.Flag("errors-json", "report scan errors on stderr as JSON Lines (path, op, message)").Bool()
+	timeout := app.Flag("timeout", "stop scanning after this duration and show the partial tree (e.g. 30s; 0 disables)").Default("0").Duration()
+	tarPath := app.Flag("tar", "stream the selected entries into a tar archive at this path (- for stdout)").PlaceHolder("FILE").String()
+	tarLog := app.Flag("tar-log", "with --tar, also print the tree to stderr").Bool()
+	treeHash := app.Flag("tree-hash", "print one Merkle hash of the tree's structure and file contents").Bool()
+	sitemap := app.Flag("sitemap", "treat path as a web root served at this base URL and print a sitemap.xml of its HTML files").PlaceHolder("BASEURL").String()
+	sitemapExts := app.Flag("sitemap-ext", "with --sitemap, list files with these extensions instead of html and htm (repeatable or comma-separated)").Strings()
+	withIDs := app.Flag("with-ids", "add a stable per-entry ID to json/ndjson/yaml output").Bool()
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	var matcher treego.NameMatcher
 	if *regexStr != "" {
-		// Prefer Go regexp for speed when possible, but fallback to a Perl-like engine
-		// to support lookaheads such as (?!...).
-		if re, goErr := regexp.Compile(*regexStr); goErr == nil {
-			matcher = goRegexpMatcher{re: re}
-		} else {
-			perl, err2 := regexp2.Compile(*regexStr, 0)
-			if err2 != nil {
-				fmt.Println("Invalid regex:", goErr)
-				return
-			}
-			perl.MatchTimeout = regexp2.DefaultMatchTimeout
-			// If user anchored to the whole name (e.g. ^...$), keep behavior.
-			// Otherwise behavior stays "match anywhere" like Go's regexp does.
-			matcher = perlRegexpMatcher{re: perl}
+		m, err := compileMatcher(*regexStr)
+		if err != nil {
+			fmt.Println("Invalid regex:", err)
+			return 0
+		}
+		matcher = m
+	}
+
+	var grepMatcher treego.NameMatcher
+	if *grep != "" {
+		m, err := compileMatcher(*grep)
+		if err != nil {
+			fmt.Println("Invalid grep pattern:", err)
+			return 0
 		}
+		grepMatcher = m
 	}
 
+	if _, ok := treego.LookupRenderer(*format); !ok {
+		fmt.Printf("Unknown format: %s (available: %s)\n", *format, strings.Join(treego.RendererNames(), ", "))
+		return 0
+	}
+
+	if *ignoreCommon {
+		common, err := treego.LoadExcludePreset("common")
+		if err != nil {
+			fmt.Println("Invalid ignore-common config:", err)
+			return 0
+		}
+		*excludePatterns = append(common, *excludePatterns...)
+	}
 	excludes, err := treego.ParseExcludeMatchers(*excludePatterns)
 	if err != nil {
 		fmt.Println("Invalid exclude pattern:", err)
-		return
+		return 0
 	}
 
 	rootPath := filepath.Clean(*path)
-	rootInfo, err := os.Stat(rootPath)
-	if err != nil {
+	if _, err := os.Stat(rootPath); err != nil {
 		fmt.Println("Invalid path:", err)
-		return
+		return 0
+	}
+
+	var recency time.Duration
+	if *modifiedWithin != "" {
+		d, err := treego.ParseRecency(*modifiedWithin)
+		if err != nil {
+			fmt.Println("Invalid --modified-within:", err)
+			return 0
+		}
+		recency = d
+	}
+
+	var mineUID uint32
+	if *mine {
+		uid, err := treego.CurrentUID()
+		if err != nil {
+			fmt.Println("Cannot use --mine:", err)
+			return 1
+		}
+		mineUID = uid
 	}
 
-	root := treego.BuildTreeSafeWithExcludes(rootPath, excludes)
+	var columns []string
+	if *columnSpec != "" {
+		columns, err = treego.ParseColumns(*columnSpec)
+		if err != nil {
+			fmt.Println("Invalid --columns:", err)
+			return 0
+		}
+		for _, c := range columns {
+			// These columns read data that is only recorded on request.
+			switch c {
+			case "owner":
+				*showOwner = true
+			case "atime":
+				*showATime = true
+			case "ctime":
+				*showCTime = true
+			case "size":
+				*du = true
+			}
+		}
+	}
+
+	extGroups, err := treego.ParseExtGroups(*extGroupSpecs)
+	if err != nil {
+		fmt.Println("Invalid --ext-group:", err)
+		return 0
+	}
+
+	var exts []string
+	for _, e := range *extensions {
+		exts = append(exts, strings.Split(e, ",")...)
+	}
+
+	var contentLimit treego.ContentLimit
+	for _, e := range *contentExts {
+		contentLimit.Extensions = append(contentLimit.Extensions, strings.Split(e, ",")...)
+	}
+	if *contentMaxSize != "" {
+		n, err := treego.ParseSize(*contentMaxSize)
+		if err != nil {
+			fmt.Println("Invalid --content-max-size:", err)
+			return 0
+		}
+		contentLimit.MaxSize = n
+	}
+
+	sortBy, _ := treego.ParseSortMode(*sortMode)
+	ioPreset, _ := treego.ParseIOProfile(*ioProfile)
+	if *hot {
+		sortBy, sortSet = treego.SortHot, true
+	}
+	if !deterministicSet {
+		// Machine formats are reproducible by default; an explicit --sort
+		// still takes precedence.
+		*deterministic = treego.IsMachineFormat(*format) && !sortSet
+	}
+	buildOpts := treego.Options{
+		Excludes:          excludes,
+		Extensions:        exts,
+		NoHiddenDirs:      *noHiddenDirs,
+		NoHiddenFiles:     *noHiddenFiles,
+		SummaryOnly:       *summaryOnly,
+		ShowOwner:         *showOwner,
+		ShowATime:         *showATime,
+		ShowCTime:         *showCTime,
+		TimeFormat:        *timeFormat,
+		RecordOwner:       *mine,
+		FollowSymlinks:    *followSymlinks,
+		MaxSymlinkDepth:   *maxSymlinkDepth,
+		IO:                ioPreset,
+		Workers:           *workers,
+		Sort:              sortBy,
+		SortCaseSensitive: !*sortIgnoreCase,
+		Deterministic:     *deterministic,
+		ExtGroups:         extGroups,
+	}
+	// Make regex match against names (like before).
+	// Users who want to match paths should use --exclude re:<expr>.
+	color, _ := treego.ParseColorMode(*colorMode)
+	if color == treego.ColorAlways && *forceColorInFile {
+		color = treego.ColorForce
+	}
+	opts := buildOpts
+	opts.Matcher = matcher
+	opts.DirsOnly = *dirsOnly
+	opts.CollapseExt = *collapseExt
+	opts.Breadcrumbs = *breadcrumbs
+	opts.Color = color
+	opts.Icons = *icons
+	opts.Hyperlinks = *hyperlinks
+	opts.ShowSize = *showSize
+	opts.RunningTotal = *runningTotal
+	opts.Columns = columns
+	opts.ShowDirSize = *du
+	opts.ShowCounts = *countsRecursive
+	opts.ShowGitAuthor = *gitAuthors
+	opts.ShowSignature = *signatures
+	opts.ShowRank = *rankRecent
+	opts.ShowSymbols = *symbols
+	opts.ShowReadme = *readmePreview
+	opts.ShowPreview = *preview > 0
+	opts.Heatmap = *heatmap
+	opts.Zebra = *zebra
+	opts.SI = *si
+	opts.ASCII = *ascii
+	opts.MaxDepth = *maxDepth
+	if *absRoot && !*fromFile {
+		if abs, err := filepath.Abs(rootPath); err == nil {
+			opts.RootLabel = abs
+		}
+	}
+	if *forCommit {
+		// Everything that would not survive a paste into a commit message
+		// is turned off; local absolute paths stay private.
+		*format = treego.DefaultFormat
+		opts.ASCII = true
+		opts.Color = treego.ColorNever
+		opts.Icons, opts.Hyperlinks, opts.Heatmap = false, false, false
+		*box, *composition, *reveal, *fitScreen = false, false, 0, false
+		opts.MaxWidth = commitWidth
+		opts.RootLabel = ""
+		if abs, err := filepath.Abs(rootPath); err == nil && !*fromFile {
+			opts.RootLabel = filepath.Base(abs)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Println("Invalid output file:", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	if *maxOutputBytes > 0 {
+		limited := treego.NewLimitWriter(out, *maxOutputBytes)
+		defer func() {
+			limited.Flush()
+			if limited.Exceeded() {
+				fmt.Fprintf(os.Stderr, "treego: output truncated after %d bytes (--max-output-bytes)\n", *maxOutputBytes)
+			}
+		}()
+		out = limited
+	}
+
+	// Progressive output is printed straight from the scan, so it only
+	// applies to a plain tree that nothing filters, annotates or
+	// summarizes afterwards.
+	if *progressive {
+		*progressive = *format == treego.DefaultFormat && !*fromFile && !*summaryOnly &&
+			len(*findGlobs) == 0 && len(*pathToPatterns) == 0 && *pathsFrom == "" && recency == 0 && *where == "" && !*mine &&
+			!*du && !*countsRecursive && !*signatures && !*gitAuthors && !*rankRecent && !*symbols && !*readmePreview && *preview <= 0 &&
+			*verify == "" && !*brokenLinks && !*caseCollisions && !*checkPathLength && !*gitLargeFiles && !*submodules && !*treeHash && *sitemap == "" &&
+			!*sizeHistogram && *level == 0 && !*extremes && *delta == "" && *tarPath == "" && grepMatcher == nil && *search == "" &&
+			!*composition && !*grouped && !*box && !*fitScreen && *reveal == 0 && !*forCommit && !*inodeSummary && !*foldIdentical
+	}
+
+	var root *treego.Node
+	if *fromFile {
+		f, err := os.Open(rootPath)
+		if err != nil {
+			fmt.Println("Invalid path:", err)
+			return 0
+		}
+		loaded, err := treego.TreeFromJSON(f)
+		f.Close()
+		if err != nil {
+			fmt.Println("Invalid tree file:", err)
+			return 0
+		}
+		root = treego.PrepareTree(loaded, buildOpts)
+	} else {
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		var (
+			res       *treego.ScanResult
+			renderErr error
+		)
+		if *progressive {
+			// The tree itself shows progress; no spinner.
+			res, renderErr = treego.RenderProgressive(ctx, out, rootPath, opts)
+		} else {
+			stopSpinner := func() {}
+			if *spinner {
+				stopSpinner = treego.StartSpinner(os.Stderr, "Scanning...")
+			}
+			res, _ = treego.ScanContext(ctx, rootPath, buildOpts)
+			stopSpinner()
+		}
+		if *errorsJSON {
+			treego.WriteErrorsJSON(os.Stderr, res.Errors)
+		} else {
+			for _, err := range res.Errors {
+				fmt.Fprintln(os.Stderr, "warning:", err)
+			}
+		}
+		if res.Partial {
+			fmt.Fprintf(os.Stderr, "warning: scan timed out after %s; results are partial\n", *timeout)
+		}
+		root = res.Root
+		if *progressive {
+			if renderErr != nil {
+				return renderFailed(renderErr)
+			}
+			return 0
+		}
+		if *summaryOnly {
+			if root != nil {
+				fmt.Printf("%d directories, %d files, %s\n", res.Dirs, res.Files, treego.HumanizeSize(res.Bytes, sizeBase(*si)))
+			}
+			return 0
+		}
+	}
 	if root == nil {
 		// Either excluded or an error occurred during traversal.
-		return
+		return 0
+	}
+
+	if len(*findGlobs) > 0 {
+		var globs []*treego.Glob
+		for _, pattern := range *findGlobs {
+			g, err := treego.CompileGlob(pattern)
+			if err != nil {
+				fmt.Println("Invalid find pattern:", err)
+				return 0
+			}
+			globs = append(globs, g)
+		}
+		if root = treego.Find(root, globs); root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if len(*pathToPatterns) > 0 {
+		targets, err := treego.ParseExcludeMatchers(*pathToPatterns)
+		if err != nil {
+			fmt.Println("Invalid path-to pattern:", err)
+			return 0
+		}
+		if root = treego.PathTo(root, targets); root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if *pathsFrom != "" {
+		var in io.Reader = os.Stdin
+		if *pathsFrom != "-" {
+			f, err := os.Open(*pathsFrom)
+			if err != nil {
+				fmt.Println("Invalid paths file:", err)
+				return 0
+			}
+			defer f.Close()
+			in = f
+		}
+		paths, err := treego.ReadPathList(in)
+		if err != nil {
+			fmt.Println("Invalid paths file:", err)
+			return 0
+		}
+		var missing []string
+		root, missing = treego.KeepPaths(root, paths)
+		for _, p := range missing {
+			fmt.Fprintln(os.Stderr, "Warning: --paths-from: not found:", p)
+		}
+		if root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if recency > 0 {
+		if root = treego.ModifiedSince(root, time.Now().Add(-recency)); root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if *where != "" {
+		pred, err := treego.ParseWhere(*where)
+		if err != nil {
+			fmt.Println("Invalid where expression:", err)
+			return 0
+		}
+		if root = treego.Where(root, pred); root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if *mine {
+		if root = treego.OwnedBy(root, mineUID); root == nil {
+			fmt.Println("No matches")
+			return 0
+		}
+	}
+
+	if *relCwd && *gitRelative {
+		fmt.Println("--rel-cwd and --git-relative cannot be combined")
+		return 0
+	}
+	// Search and grep paths are rebased only when they are printed, since
+	// the rebased paths need not resolve from the working directory.
+	var displayBase string
+	if *relCwd {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Println("Failed to resolve working directory:", err)
+			return 0
+		}
+		displayBase = cwd
+	}
+	if *gitRelative {
+		repo, err := treego.FindRepoRoot(rootPath)
+		if err != nil {
+			fmt.Println("Failed to resolve repository root:", err)
+			return 0
+		}
+		displayBase = repo
+	}
+
+	if len(contentLimit.Extensions) > 0 || contentLimit.MaxSize > 0 {
+		treego.LimitContent(root, contentLimit)
+	}
+
+	if *du || *countsRecursive {
+		// One pass fills in every directory's totals; the renderers and
+		// summaries reuse them.
+		treego.AggregateAll(root)
+	}
+
+	if *signatures {
+		treego.DetectSignatures(root)
+	}
+
+	if *gitAuthors {
+		repo, err := treego.FindRepoRoot(rootPath)
+		if err == nil {
+			err = treego.AnnotateGitAuthors(root, repo)
+		}
+		if err != nil {
+			// The tree is still useful without annotations.
+			fmt.Fprintln(os.Stderr, "Warning: --git-authors:", err)
+		}
+	}
+
+	if *symbols {
+		treego.ExtractSymbols(root)
 	}
 
-	if *search != "" {
-		treego.SearchDFS(root, *search)
+	if *readmePreview {
+		treego.AttachReadmePreviews(root)
+	}
+
+	if *preview > 0 {
+		treego.AttachPreviews(root, *preview)
+	}
+
+	if *rankRecent {
+		treego.RankRecent(root, *rankTop)
+	}
+
+	if *withIDs {
+		treego.AssignStableIDs(root)
+	}
+
+	if *verify != "" {
+		f, err := os.Open(*verify)
+		if err != nil {
+			fmt.Println("Invalid manifest:", err)
+			return 1
+		}
+		manifest, err := treego.ReadManifest(f)
+		f.Close()
+		if err != nil {
+			fmt.Println("Invalid manifest:", err)
+			return 1
+		}
+		v := treego.VerifyManifest(root, manifest)
+		status := 0
+		if err := treego.PrintVerification(out, v); err != nil {
+			status = renderFailed(err)
+		}
+		if !v.OK() {
+			status = 1
+		}
+		return status
+	}
+
+	if *brokenLinks {
+		links := treego.FindBrokenLinks(root)
+		status := 0
+		if err := treego.PrintBrokenLinks(out, links); err != nil {
+			status = renderFailed(err)
+		}
+		if len(links) > 0 {
+			status = 1
+		}
+		return status
+	}
+
+	if *caseCollisions {
+		if err := treego.PrintCaseCollisions(out, treego.FindCaseCollisions(root)); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *noExtWarn {
+		bare := treego.WithoutExtension(root)
+		if bare == nil {
+			fmt.Fprintln(out, "No files without an extension")
+			return 0
+		}
+		if err := treego.RenderNode(bare, out, *format, opts); err != nil {
+			renderFailed(err)
+		}
+		return 1
+	}
+
+	if *gitLargeFiles {
+		warnAt, err := treego.ParseSize(*gitLargeWarn)
+		if err != nil {
+			fmt.Println("Invalid --git-large-warn:", err)
+			return 1
+		}
+		limit, err := treego.ParseSize(*gitLargeLimit)
+		if err != nil {
+			fmt.Println("Invalid --git-large-limit:", err)
+			return 1
+		}
+		repo, err := treego.FindRepoRoot(rootPath)
+		if err != nil {
+			fmt.Println("Failed to resolve repository root:", err)
+			return 1
+		}
+		large, err := treego.FindGitLargeFiles(root, repo, warnAt, limit)
+		if err != nil {
+			fmt.Println("Failed to list repository files:", err)
+			return 1
+		}
+		status := 0
+		if err := treego.PrintGitLargeFiles(out, large, warnAt, limit); err != nil {
+			status = renderFailed(err)
+		}
+		for _, f := range large {
+			if f.OverLimit {
+				status = 1
+			}
+		}
+		return status
+	}
+
+	if *checkPathLength {
+		long := treego.FindLongPaths(root, treego.MaxPath)
+		status := 0
+		if err := treego.PrintLongPaths(out, long, treego.MaxPath); err != nil {
+			status = renderFailed(err)
+		}
+		if len(long) > 0 {
+			status = 1
+		}
+		return status
+	}
+
+	if *submodules {
+		repo, subs, err := treego.LoadSubmodules(rootPath)
+		if err != nil {
+			fmt.Println("Failed to read submodules:", err)
+			return 1
+		}
+		if err := treego.PrintSubmodules(out, treego.SubmoduleDirs(root, repo, subs)); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *treeHash {
+		sum, err := treego.TreeHash(root)
+		if err != nil {
+			fmt.Println("Failed to hash tree:", err)
+			return 1
+		}
+		fmt.Fprintln(out, sum)
+		return 0
+	}
+
+	if *sitemap != "" {
+		if u, err := url.Parse(*sitemap); err != nil || u.Scheme == "" || u.Host == "" {
+			fmt.Println("Invalid --sitemap base URL:", *sitemap)
+			return 1
+		}
+		pageExts := treego.SitemapExtensions
+		if len(*sitemapExts) > 0 {
+			pageExts = nil
+			for _, e := range *sitemapExts {
+				pageExts = append(pageExts, strings.Split(e, ",")...)
+			}
+		}
+		if err := treego.TreeToSitemapExt(root, *sitemap, pageExts, out); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *sizeHistogram {
+		if err := treego.PrintSizeHistogram(out, treego.SizeHistogram(root)); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *level > 0 {
+		if err := treego.PrintLevel(out, root, *level, opts); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *extremes {
+		if err := treego.PrintDepthExtremes(out, treego.FindDepthExtremes(root)); err != nil {
+			return renderFailed(err)
+		}
+		return 0
+	}
+
+	if *delta != "" {
+		return runDelta(out, root, *delta, *detectRenames, opts)
+	}
+
+	if *tarPath != "" {
+		if *tarLog {
+			if err := treego.RenderNode(root, os.Stderr, treego.DefaultFormat, opts); err != nil {
+				fmt.Fprintln(os.Stderr, "Render failed:", err)
+			}
+		}
+		if err := runTar(root, *tarPath); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to write archive:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if grepMatcher != nil || *search != "" {
+		q := treego.MatchQuery{Name: *search, Content: grepMatcher, Any: *matchAny}
+		if err := printSearch(out, root, q, *searchCounts, displayBase); err != nil {
+			return renderFailed(err)
+		}
 	} else {
-		fmt.Println(rootInfo.Name())
-		// Make regex match against names (like before).
-		// Users who want to match paths should use --exclude re:<expr>.
-		treego.PrintTreeDFS(root, "", "", matcher, *dirsOnly)
+		// Folding only shortens what is shown; summaries use the full tree.
+		shown := root
+		if *foldIdentical {
+			shown = treego.FoldIdentical(root)
+		}
+		// The bar is a terminal summary; structured formats stay parseable.
+		if *composition && *format == treego.DefaultFormat {
+			if err := treego.PrintComposition(out, root, opts); err != nil {
+				return renderFailed(err)
+			}
+		}
+		if *grouped && *format == treego.DefaultFormat {
+			if err := treego.RenderGrouped(out, shown, opts); err != nil {
+				return renderFailed(err)
+			}
+		} else if *box && *format == treego.DefaultFormat {
+			// The tree is drawn into a buffer first, so colors are
+			// decided for out rather than for the buffer.
+			boxOpts := opts
+			boxOpts.Color = treego.ResolveColor(out, opts.Color)
+			var buf bytes.Buffer
+			err := treego.RenderNode(shown, &buf, *format, boxOpts)
+			if err == nil {
+				// The root line becomes the title instead of repeating it
+				// inside the box.
+				title, body, _ := strings.Cut(buf.String(), "\n")
+				err = treego.DrawBox(out, body, title)
+			}
+			if err != nil {
+				return renderFailed(err)
+			}
+		} else if *fitScreen && *format == treego.DefaultFormat {
+			if err := treego.FitScreen(out, shown, opts, treego.TerminalHeight(out)); err != nil {
+				return renderFailed(err)
+			}
+		} else if *reveal > 0 && *format == treego.DefaultFormat {
+			if err := treego.Reveal(out, shown, opts, *reveal); err != nil {
+				return renderFailed(err)
+			}
+		} else if *forCommit {
+			if err := renderFenced(out, shown, opts); err != nil {
+				return renderFailed(err)
+			}
+		} else if err := treego.RenderNode(shown, out, *format, opts); err != nil {
+			return renderFailed(err)
+		}
+		if *inodeSummary && *format == treego.DefaultFormat {
+			fmt.Fprintln(out)
+			if err := treego.PrintInodeSummary(out, treego.CountInodes(root), opts); err != nil {
+				return renderFailed(err)
+			}
+		}
+	}
+	return 0
+}
+
+// printSearch prints the --grep matches for q, or without a content
+// condition the --search results (as counts when counts is set). Paths are
+// rebased onto base, when set, once every file has been read.
+func printSearch(w io.Writer, root *treego.Node, q treego.MatchQuery, counts bool, base string) error {
+	var matches []treego.Match
+	if q.Content != nil {
+		matches = treego.SearchContent(root, q)
+	}
+	if base != "" {
+		if err := treego.RebasePaths(root, base); err != nil {
+			return err
+		}
 	}
+	switch {
+	case q.Content != nil:
+		return treego.PrintMatches(w, matches)
+	case counts:
+		return treego.PrintSearchCounts(w, treego.SearchCounts(root, q.Name))
+	default:
+		return treego.PrintSearch(w, root, q.Name)
+	}
+}
+
+// renderFailed reports an output error on stderr and returns exit status 1.
+// EPIPE means the reader of a pipe or FIFO has gone away, which is how
+// streaming consumers (head, a closed --output fifo) signal they are done,
+// so treego stops quietly with status 0 instead.
+func renderFailed(err error) int {
+	if errors.Is(err, syscall.EPIPE) {
+		return 0
+	}
+	if errors.Is(err, treego.ErrOutputLimit) {
+		// Reported once the run ends, see --max-output-bytes.
+		return 0
+	}
+	fmt.Fprintln(os.Stderr, "Render failed:", err)
+	return 1
+}
+
+// commitWidth is the line limit for --for-commit, the width git tooling
+// conventionally wraps commit message bodies at.
+const commitWidth = 72
+
+// renderFenced writes the tree inside a Markdown code fence.
+func renderFenced(w io.Writer, root *treego.Node, opts treego.Options) error {
+	if _, err := fmt.Fprintln(w, "```"); err != nil {
+		return err
+	}
+	if err := treego.RenderNode(root, w, treego.DefaultFormat, opts); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "```")
+	return err
+}
+
+// sizeBase returns the HumanizeSize base selected by --si.
+func sizeBase(si bool) int64 {
+	if si {
+		return treego.SIBase
+	}
+	return treego.BinaryBase
+}
+
+// runTar archives root into path, or to stdout when path is "-". A failed
+// archive file is removed rather than left half-written.
+func runTar(root *treego.Node, path string) error {
+	if path == "-" {
+		return treego.WriteTar(root, os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = treego.WriteTar(root, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
 }
 
+// runDelta prints what changed since the snapshot in statePath and then
+// replaces the snapshot with the current tree. With renames, file contents
+// are hashed and stored in the snapshot so moves can be matched next time.
+// It returns the exit status.
+func runDelta(out io.Writer, root *treego.Node, statePath string, renames bool, opts treego.Options) int {
+	if renames {
+		if err := treego.HashFiles(root); err != nil {
+			fmt.Fprintln(os.Stderr, "treego: hashing files:", err)
+		}
+	}
+	prev, err := treego.LoadSnapshot(statePath)
+	switch {
+	case os.IsNotExist(err):
+		fmt.Println("No previous snapshot; recorded current tree in", statePath)
+	case err != nil:
+		fmt.Println("Invalid state file:", err)
+		return 0
+	default:
+		d := treego.DiffTrees(prev, root)
+		if renames {
+			d = treego.DetectRenames(d)
+		}
+		// The snapshot is kept unless the output was only cut short by
+		// --max-output-bytes.
+		if err := treego.PrintDelta(out, d, opts); err != nil && !errors.Is(err, treego.ErrOutputLimit) {
+			return renderFailed(err)
+		}
+	}
+	if err := treego.SaveSnapshot(statePath, root); err != nil {
+		fmt.Println("Failed to save snapshot:", err)
+	}
+	return 0
+}
@@ -3,8 +3,13 @@ module github.com/marcuwynu23/treego
 go 1.23.5
 
 require (
-	github.com/alecthomas/kingpin/v2 v2.4.0 // indirect
+	github.com/alecthomas/kingpin/v2 v2.4.0
+	github.com/dlclark/regexp2 v1.11.5
+	golang.org/x/sys v0.35.0
+	golang.org/x/term v0.34.0
+)
+
+require (
 	github.com/alecthomas/units v0.0.0-20211218093645-b94a6e3cc137 // indirect
-	github.com/dlclark/regexp2 v1.11.5 // indirect
 	github.com/xhit/go-str2duration/v2 v2.1.0 // indirect
 )
@@ -0,0 +1,137 @@
+package treego_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestAggregateAll(t *testing.T) {
+	root := statsTree()
+	got := treego.AggregateAll(root)
+	if want := (treego.DirTotals{Dirs: 1, Files: 4, Bytes: 1000}); got != want || *root.Totals != want {
+		t.Errorf("AggregateAll = %+v (root %+v); want %+v", got, root.Totals, want)
+	}
+	src := root.Children[0]
+	if want := (treego.DirTotals{Files: 2, Bytes: 625}); src.Totals == nil || *src.Totals != want {
+		t.Errorf("src totals = %+v; want %+v", src.Totals, want)
+	}
+	if root.Children[1].Totals != nil {
+		t.Error("Expected no totals on files")
+	}
+
+	out := renderTreeOf(t, statsTree(), treego.Options{ShowDirSize: true, ShowCounts: true})
+	for _, line := range []string{"[1000 B]  root (4 files, 1 dir)\n", "── [625 B]  src (2 files, 0 dirs)\n", "── README.md\n"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in output, got:\n%s", line, out)
+		}
+	}
+
+	var js strings.Builder
+	if err := treego.TreeToJSON(root, &js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Totals treego.DirTotals `json:"totals"`
+	}
+	if err := json.Unmarshal([]byte(js.String()), &decoded); err != nil || decoded.Totals.Files != 4 {
+		t.Errorf("Expected totals in JSON, got %+v, %v", decoded.Totals, err)
+	}
+}
+
+// deepTree is a chain of depth directories, each holding files files.
+func deepTree(depth, files int) *treego.Node {
+	root := &treego.Node{Name: "root", IsDir: true}
+	cur := root
+	for d := 0; d < depth; d++ {
+		for f := 0; f < files; f++ {
+			cur.Children = append(cur.Children, &treego.Node{Name: "f", Size: 10})
+		}
+		next := &treego.Node{Name: "d", IsDir: true}
+		cur.Children = append(cur.Children, next)
+		cur = next
+	}
+	return root
+}
+
+// naiveTotals recomputes a subtree's totals from scratch, as a renderer
+// without memoized totals would for every directory it prints.
+func naiveTotals(n *treego.Node) treego.DirTotals {
+	var t treego.DirTotals
+	for _, c := range n.Children {
+		if !c.IsDir {
+			t.Files++
+			t.Bytes += c.Size
+			continue
+		}
+		ct := naiveTotals(c)
+		t.Dirs += ct.Dirs + 1
+		t.Files += ct.Files
+		t.Bytes += ct.Bytes
+	}
+	return t
+}
+
+func BenchmarkAggregateAll(b *testing.B) {
+	root := deepTree(2000, 5)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		treego.AggregateAll(root)
+	}
+}
+
+func BenchmarkAggregateNaive(b *testing.B) {
+	root := deepTree(2000, 5)
+	var each func(n *treego.Node)
+	each = func(n *treego.Node) {
+		if !n.IsDir {
+			return
+		}
+		naiveTotals(n)
+		for _, c := range n.Children {
+			each(c)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		each(root)
+	}
+}
+
+func TestRenderGrouped(t *testing.T) {
+	root := &treego.Node{Name: "ws", IsDir: true, Children: []*treego.Node{
+		{Name: "api", IsDir: true, Children: []*treego.Node{
+			{Name: "cmd", IsDir: true, Children: []*treego.Node{{Name: "main.go", Size: 100}}},
+			{Name: "go.mod", Size: 20},
+		}},
+		{Name: "web", IsDir: true, Children: []*treego.Node{{Name: "index.html", Size: 2048}}},
+		{Name: "notes.txt", Size: 5},
+	}}
+	var buf bytes.Buffer
+	if err := treego.RenderGrouped(&buf, root, treego.Options{MaxDepth: 1, RootLabel: "/abs/ws"}); err != nil {
+		t.Fatalf("RenderGrouped failed: %v", err)
+	}
+	want := "── api ──\n" +
+		"api\n" +
+		"├── cmd\n" +
+		"└── go.mod\n" +
+		"2 directories, 2 files, 120 B\n" +
+		"\n" +
+		"── web ──\n" +
+		"web\n" +
+		"└── index.html\n" +
+		"1 directories, 1 files, 2.0 KiB\n" +
+		"\n" +
+		"── files in ws ──\n" +
+		"ws\n" +
+		"└── notes.txt\n" +
+		"0 directories, 1 files, 5 B\n" +
+		"\n" +
+		"Total: 3 sections, 3 directories, 4 files, 2.1 KiB\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,157 @@
+package treego_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestWriteTar(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	target := filepath.Join(tmpDir, "dir1", "subdir1", "file4.go")
+	if err := os.WriteFile(target, []byte("package main\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(target, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(target, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	excludes, _ := treego.ParseExcludeMatchers([]string{"node_modules"})
+	root := treego.BuildFilteredTree(tmpDir, treego.Options{Excludes: excludes})
+	var buf bytes.Buffer
+	if err := treego.WriteTar(root, &buf); err != nil {
+		t.Fatalf("WriteTar failed: %v", err)
+	}
+
+	headers := make(map[string]*tar.Header)
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Reading archive failed: %v", err)
+		}
+		headers[hdr.Name] = hdr
+		if hdr.Name == "dir1/subdir1/file4.go" {
+			body, _ := io.ReadAll(tr)
+			if string(body) != "package main\n" {
+				t.Errorf("Unexpected content %q", body)
+			}
+		}
+	}
+
+	for _, name := range []string{"file1.txt", "file2.go", "dir1/", "dir1/file3.txt", "dir1/subdir1/", "dir1/subdir1/file4.go", "dir2/", "dir2/file5.txt"} {
+		if headers[name] == nil {
+			t.Errorf("Expected %s in archive", name)
+		}
+	}
+	if len(headers) != 8 {
+		t.Errorf("Expected 8 entries without node_modules, got %d", len(headers))
+	}
+	if hdr := headers["dir1/subdir1/file4.go"]; hdr != nil {
+		if !hdr.ModTime.Equal(mtime) {
+			t.Errorf("Expected mtime %v, got %v", mtime, hdr.ModTime)
+		}
+		if hdr.Mode&0777 != 0640 {
+			t.Errorf("Expected mode 0640, got %o", hdr.Mode&0777)
+		}
+	}
+}
+
+// extractTar unpacks the archive in r under dest, creating directories,
+// regular files and symlinks.
+func extractTar(t *testing.T, r io.Reader, dest string) {
+	t.Helper()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Reading archive failed: %v", err)
+		}
+		path := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			var body []byte
+			if body, err = io.ReadAll(tr); err == nil {
+				err = os.WriteFile(path, body, 0644)
+			}
+		case tar.TypeSymlink:
+			err = os.Symlink(hdr.Linkname, path)
+		default:
+			t.Fatalf("Unexpected entry type %q for %s", hdr.Typeflag, hdr.Name)
+		}
+		if err != nil {
+			t.Fatalf("Extracting %s failed: %v", hdr.Name, err)
+		}
+	}
+}
+
+func TestWriteTarSymlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "real", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "real", "sub", "a.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"dirlink": "real", "filelink": filepath.Join("real", "sub", "a.txt")} {
+		if err := os.Symlink(target, filepath.Join(tmpDir, link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		follow bool
+	}{
+		{"links stored as links", false},
+		{"followed links stored as targets", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resetGlobalState()
+			root := treego.BuildFilteredTree(tmpDir, treego.Options{FollowSymlinks: tc.follow})
+			var buf bytes.Buffer
+			if err := treego.WriteTar(root, &buf); err != nil {
+				t.Fatalf("WriteTar failed: %v", err)
+			}
+			dest := t.TempDir()
+			extractTar(t, &buf, dest)
+
+			for _, name := range []string{"dirlink", "filelink"} {
+				info, err := os.Lstat(filepath.Join(dest, name))
+				if err != nil {
+					t.Fatalf("Expected %s in archive: %v", name, err)
+				}
+				if isLink := info.Mode()&os.ModeSymlink != 0; isLink == tc.follow {
+					t.Errorf("%s: expected symlink %v, got mode %v", name, !tc.follow, info.Mode())
+				}
+			}
+			for _, name := range []string{"filelink", filepath.Join("dirlink", "sub", "a.txt")} {
+				body, err := os.ReadFile(filepath.Join(dest, name))
+				if err != nil || string(body) != "hello\n" {
+					t.Errorf("%s: expected %q, got %q (%v)", name, "hello\n", body, err)
+				}
+			}
+		})
+	}
+}
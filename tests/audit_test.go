@@ -0,0 +1,80 @@
+package treego_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestFindCaseCollisions(t *testing.T) {
+	root := &treego.Node{Name: "root", Path: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "Docs", Path: "root/Docs", IsDir: true, Children: []*treego.Node{
+			{Name: "README.md", Path: "root/Docs/README.md"},
+			{Name: "readme.md", Path: "root/Docs/readme.md"},
+			{Name: "Readme.MD", Path: "root/Docs/Readme.MD"},
+		}},
+		{Name: "docs", Path: "root/docs", IsDir: true},
+		{Name: "main.go", Path: "root/main.go"},
+	}}
+
+	collisions := treego.FindCaseCollisions(root)
+	if len(collisions) != 2 {
+		t.Fatalf("Expected 2 collision groups, got %+v", collisions)
+	}
+	if collisions[0].Dir != root || strings.Join(collisions[0].Names, ",") != "Docs,docs" {
+		t.Errorf("Unexpected first group: %+v", collisions[0])
+	}
+	if len(collisions[1].Names) != 3 {
+		t.Errorf("Expected three colliding readme files, got %v", collisions[1].Names)
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintCaseCollisions(&buf, collisions); err != nil {
+		t.Fatalf("PrintCaseCollisions failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "root/Docs: README.md, readme.md, Readme.MD\n") {
+		t.Errorf("Unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	treego.PrintCaseCollisions(&buf, treego.FindCaseCollisions(sampleTree()))
+	if buf.String() != "No case collisions found\n" {
+		t.Errorf("Expected no collisions, got %s", buf.String())
+	}
+}
+
+func TestFindLongPaths(t *testing.T) {
+	deep := strings.Repeat("d", 200)
+	root := &treego.Node{Name: "root", Path: "/root", IsDir: true, Children: []*treego.Node{
+		{Name: deep, IsDir: true, Children: []*treego.Node{
+			{Name: strings.Repeat("f", 50) + ".txt"},
+			{Name: "é.txt"},
+		}},
+		{Name: "short.txt"},
+	}}
+
+	long := treego.FindLongPaths(root, treego.MaxPath)
+	want := "/root/" + deep + "/" + strings.Repeat("f", 50) + ".txt"
+	if len(long) != 1 || long[0].Path != filepath.FromSlash(want) || long[0].Length != 261 {
+		t.Fatalf("Expected only the 261-character path, got %+v", long)
+	}
+	if got := treego.FindLongPaths(root, 205); len(got) != 3 || got[0].Path != filepath.FromSlash("/root/"+deep) {
+		t.Errorf("Expected the directory and both files over 205 characters, got %d paths", len(got))
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintLongPaths(&buf, long, treego.MaxPath); err != nil {
+		t.Fatalf("PrintLongPaths failed: %v", err)
+	}
+	if buf.String() != " 261  "+long[0].Path+"\n" {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+	buf.Reset()
+	treego.PrintLongPaths(&buf, nil, treego.MaxPath)
+	if buf.String() != "No paths longer than 260 characters\n" {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package treego_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	cases := map[string]int{
+		"":                                       0,
+		"main.go":                                7,
+		"├── a":                                  5,
+		"\x1b[1;34msrc\x1b[0m":                   3,
+		"\x1b]8;;file:///x\x1b\\x\x1b]8;;\x1b\\": 1,
+		"📁 src":                                  6,
+		"文件.txt":                                 8,
+		"é":                                     1,
+	}
+	for in, want := range cases {
+		if got := treego.DisplayWidth(in); got != want {
+			t.Errorf("DisplayWidth(%q) = %d; want %d", in, got, want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"main.go", "main.go"},
+		{"a-long-file-name.txt", "a-long-..."},
+		{"文件文件文件.txt", "文件文..."},
+		{"\x1b[2m├── \x1b[0mlong-name.txt", "\x1b[2m├── \x1b[0mlon\x1b[0m..."},
+	}
+	for _, c := range cases {
+		if got := treego.TruncateWidth(c.in, 10); got != c.want {
+			t.Errorf("TruncateWidth(%q, 10) = %q; want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestDrawBox(t *testing.T) {
+	t.Run("sized to widest line with title", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.DrawBox(&buf, "└── 文件\n", "root"); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		want := "" +
+			"┌─ root ───┐\n" +
+			"│ └── 文件 │\n" +
+			"└──────────┘\n"
+		if buf.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("escape codes do not widen the box", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.DrawBox(&buf, "\x1b[1;34mab\x1b[0m\nabcd", ""); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		for _, l := range lines {
+			if got := treego.DisplayWidth(l); got != 8 {
+				t.Errorf("Expected every line to be 8 cells wide, got %d for %q", got, l)
+			}
+		}
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.DrawBox(&buf, "", "file.txt"); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		if want := "┌─ file.txt ──┐\n└─────────────┘\n"; buf.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("long title widens the box", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.DrawBox(&buf, "a", "/a/long/title"); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		width := treego.DisplayWidth(lines[0])
+		for _, l := range lines {
+			if treego.DisplayWidth(l) != width {
+				t.Errorf("Expected aligned box, got:\n%s", buf.String())
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,382 @@
+package treego_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func countNodes(n *treego.Node) (dirs, files int) {
+	if n.IsDir {
+		dirs++
+	} else {
+		files++
+	}
+	for _, c := range n.Children {
+		d, f := countNodes(c)
+		dirs += d
+		files += f
+	}
+	return dirs, files
+}
+
+func TestBuildFilteredTree(t *testing.T) {
+	t.Run("extension filter skips files during build", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{Extensions: []string{"GO"}})
+		if root == nil {
+			t.Fatal("Expected non-nil root node")
+		}
+
+		var walk func(n *treego.Node)
+		walk = func(n *treego.Node) {
+			if !n.IsDir && filepath.Ext(n.Name) != ".go" {
+				t.Errorf("Expected only .go files, found %s", n.Path)
+			}
+			for _, c := range n.Children {
+				walk(c)
+			}
+		}
+		walk(root)
+
+		// Directories are still traversed: dir1/subdir1/file4.go is reachable.
+		dirs, files := countNodes(root)
+		if files != 2 {
+			t.Errorf("Expected 2 .go files, got %d", files)
+		}
+		if dirs != 5 {
+			t.Errorf("Expected all 5 directories to be kept, got %d", dirs)
+		}
+	})
+
+	t.Run("extensions accept dots and multi-part suffixes", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir := createContentDir(t, map[string]string{
+			"a.tar.gz": "",
+			"b.gz":     "",
+			"c.txt":    "",
+		})
+
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{Extensions: []string{".tar.gz", "txt"}})
+		if root == nil {
+			t.Fatal("Expected non-nil root node")
+		}
+		if len(root.Children) != 2 || root.Children[0].Name != "a.tar.gz" || root.Children[1].Name != "c.txt" {
+			t.Errorf("Unexpected children: %v", matchedNodeNames(root.Children))
+		}
+	})
+
+	t.Run("excludes still apply", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		excludes, err := treego.ParseExcludeMatchers([]string{"dir1"})
+		if err != nil {
+			t.Fatalf("Failed to parse excludes: %v", err)
+		}
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{Excludes: excludes, Extensions: []string{"go"}})
+		if root == nil {
+			t.Fatal("Expected non-nil root node")
+		}
+		if _, files := countNodes(root); files != 1 {
+			t.Errorf("Expected only file2.go to remain, got %d files", files)
+		}
+	})
+}
+
+func TestHiddenFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []string{".env", "main.go", ".git/config", "src/.keep", "src/app.go"} {
+		p := filepath.Join(tmpDir, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(root *treego.Node) map[string]bool {
+		out := make(map[string]bool)
+		var walk func(n *treego.Node)
+		walk = func(n *treego.Node) {
+			for _, c := range n.Children {
+				out[c.Name] = true
+				walk(c)
+			}
+		}
+		walk(root)
+		return out
+	}
+
+	cases := []struct {
+		name          string
+		opts          treego.Options
+		want, notWant []string
+	}{
+		{"dirs only", treego.Options{NoHiddenDirs: true}, []string{".env", ".keep", "src"}, []string{".git", "config"}},
+		{"files only", treego.Options{NoHiddenFiles: true}, []string{".git", "config"}, []string{".env", ".keep"}},
+		{"both", treego.Options{NoHiddenDirs: true, NoHiddenFiles: true}, []string{"main.go", "app.go"}, []string{".env", ".git", ".keep"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resetGlobalState()
+			built := names(treego.BuildFilteredTree(tmpDir, c.opts))
+
+			resetGlobalState()
+			prepared := names(treego.PrepareTree(treego.BuildFilteredTree(tmpDir, treego.Options{}), c.opts))
+
+			for _, got := range []map[string]bool{built, prepared} {
+				for _, n := range c.want {
+					if !got[n] {
+						t.Errorf("Expected %s to be kept", n)
+					}
+				}
+				for _, n := range c.notWant {
+					if got[n] {
+						t.Errorf("Expected %s to be skipped", n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "real", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	links := [][2]string{{"real", "l1"}, {"l1", "l2"}, {"l2", "l3"}, {"..", "real/sub/up"}}
+	for _, l := range links {
+		if err := os.Symlink(l[0], filepath.Join(tmpDir, l[1])); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	byName := func(n *treego.Node) map[string]*treego.Node {
+		out := make(map[string]*treego.Node)
+		for _, c := range n.Children {
+			out[c.Name] = c
+		}
+		return out
+	}
+
+	t.Run("not followed by default", func(t *testing.T) {
+		resetGlobalState()
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{})
+		if l1 := byName(root)["l1"]; l1 == nil || l1.IsDir {
+			t.Errorf("Expected l1 listed as a plain entry, got %+v", l1)
+		}
+	})
+
+	t.Run("chains beyond the limit are marked", func(t *testing.T) {
+		resetGlobalState()
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{FollowSymlinks: true, MaxSymlinkDepth: 2})
+		entries := byName(root)
+		if l1 := entries["l1"]; l1 == nil || !l1.IsDir || l1.LinkError != "" || len(l1.Children) != 1 {
+			t.Errorf("Expected l1 followed into real, got %+v", l1)
+		}
+		if l3 := entries["l3"]; l3 == nil || l3.LinkError != "too many links" {
+			t.Errorf("Expected l3 marked as too many links, got %+v", l3)
+		}
+
+		output := renderTreeOf(t, root, treego.Options{})
+		if !strings.Contains(output, "l3 [too many links]") || !strings.Contains(output, "up [already visited]") {
+			t.Errorf("Expected link notes in output, got:\n%s", output)
+		}
+	})
+
+	t.Run("default depth follows the chain", func(t *testing.T) {
+		resetGlobalState()
+		root := treego.BuildFilteredTree(tmpDir, treego.Options{FollowSymlinks: true})
+		if l3 := byName(root)["l3"]; l3 == nil || !l3.IsDir || l3.LinkError == "too many links" {
+			t.Errorf("Expected l3 resolved within the default limit, got %+v", l3)
+		}
+	})
+}
+
+func TestBuildTreeContext(t *testing.T) {
+	t.Run("cancelled context returns truncated root", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		root, partial := treego.BuildTreeContext(ctx, tmpDir, treego.Options{})
+		if root == nil {
+			t.Fatal("Expected non-nil root node")
+		}
+		if !partial {
+			t.Error("Expected partial result for cancelled context")
+		}
+		if !root.Truncated || len(root.Children) != 0 {
+			t.Errorf("Expected empty truncated root, got truncated=%v with %d children", root.Truncated, len(root.Children))
+		}
+
+		var buf bytes.Buffer
+		if err := treego.Render(root, &buf, "tree", treego.Options{}); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "[truncated]") {
+			t.Errorf("Expected [truncated] marker in output, got %q", buf.String())
+		}
+	})
+
+	t.Run("live context builds full tree", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		root, partial := treego.BuildTreeContext(context.Background(), tmpDir, treego.Options{})
+		if root == nil || partial {
+			t.Fatalf("Expected complete tree, got root=%v partial=%v", root, partial)
+		}
+		if dirs, _ := countNodes(root); dirs != 5 {
+			t.Errorf("Expected 5 directories, got %d", dirs)
+		}
+	})
+}
+
+func TestBuildHooks(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	// Hooks are serialized by the builder, so plain appends are safe.
+	var order []*treego.Node
+	var files, dirs int
+	opts := treego.Options{
+		OnFile: func(n *treego.Node) { files++; order = append(order, n) },
+		OnDir:  func(n *treego.Node) { dirs++; order = append(order, n) },
+	}
+	root := treego.BuildFilteredTree(tmpDir, opts)
+	if root == nil {
+		t.Fatal("Expected non-nil root node")
+	}
+
+	wantDirs, wantFiles := countNodes(root)
+	if dirs != wantDirs || files != wantFiles {
+		t.Errorf("Expected %d dirs and %d files reported, got %d and %d", wantDirs, wantFiles, dirs, files)
+	}
+	if len(order) == 0 || order[len(order)-1] != root {
+		t.Fatal("Expected root directory to be reported last")
+	}
+
+	// Every entry is reported before the directory that contains it.
+	pos := make(map[*treego.Node]int, len(order))
+	for i, n := range order {
+		pos[n] = i
+	}
+	var walk func(n *treego.Node)
+	walk = func(n *treego.Node) {
+		for _, c := range n.Children {
+			if pos[c] >= pos[n] {
+				t.Errorf("Expected %s to be reported before %s", c.Path, n.Path)
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+}
+
+func matchedNodeNames(nodes []*treego.Node) []string {
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
+
+// createWideDir creates dirs*files entries where only one file per directory
+// has the .go extension.
+func createWideDir(b *testing.B, dirs, files int) string {
+	tmpDir := b.TempDir()
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(tmpDir, fmt.Sprintf("dir%d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatalf("Failed to create dir: %v", err)
+		}
+		for f := 0; f < files; f++ {
+			name := fmt.Sprintf("file%d.txt", f)
+			if f == 0 {
+				name = "main.go"
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+				b.Fatalf("Failed to create file: %v", err)
+			}
+		}
+	}
+	return tmpDir
+}
+
+// Compare allocations (-benchmem) between an unfiltered build and a build
+// that keeps only .go files.
+func BenchmarkBuildFilteredTreeAll(b *testing.B) {
+	tmpDir := createWideDir(b, 20, 50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resetGlobalState()
+		treego.BuildFilteredTree(tmpDir, treego.Options{})
+	}
+}
+
+func BenchmarkBuildFilteredTreeExt(b *testing.B) {
+	tmpDir := createWideDir(b, 20, 50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resetGlobalState()
+		treego.BuildFilteredTree(tmpDir, treego.Options{Extensions: []string{"go"}})
+	}
+}
+
+func TestFindBrokenLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "target.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	links := [][2]string{{"target.txt", "ok"}, {"missing.txt", "sub/dangling"}, {"loop", "loop"}, {"sub", "dirlink"}}
+	for _, l := range links {
+		if err := os.Symlink(l[0], filepath.Join(tmpDir, l[1])); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(tmpDir, treego.Options{})
+	got := treego.FindBrokenLinks(root)
+	want := []treego.BrokenLink{
+		{Path: filepath.Join(tmpDir, "sub", "dangling"), Target: "missing.txt"},
+		{Path: filepath.Join(tmpDir, "loop"), Target: "loop"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d broken links, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("link %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintBrokenLinks(&buf, got[:1]); err != nil {
+		t.Fatalf("PrintBrokenLinks failed: %v", err)
+	}
+	if want := filepath.Join(tmpDir, "sub", "dangling") + " -> missing.txt\n"; buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
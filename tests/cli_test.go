@@ -0,0 +1,159 @@
+package treego_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+// buildCLI compiles the treego command into a temporary directory and
+// returns the path of the binary.
+func buildCLI(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds the treego command")
+	}
+	bin := filepath.Join(t.TempDir(), "treego")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	if out, err := exec.Command("go", "build", "-o", bin, "github.com/marcuwynu23/treego/cmd/treego").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestCLIRelativePathsFromSubdir(t *testing.T) {
+	bin := buildCLI(t)
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pkg := filepath.Join(repo, "sub", "pkg")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pkg, "a.go"), []byte("package pkg // TODO\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		dir  string
+		args []string
+		want string
+	}{
+		{"git-relative grep", filepath.Join(repo, "sub"), []string{"pkg", "--grep", "TODO", "--git-relative"},
+			filepath.FromSlash("sub/pkg/a.go") + "\n  1: package pkg // TODO\n"},
+		{"rel-cwd grep", pkg, []string{filepath.Join("..", "pkg"), "--grep", "TODO", "--rel-cwd"},
+			"a.go\n  1: package pkg // TODO\n"},
+		{"rel-cwd search", filepath.Join(repo, "sub"), []string{repo, "--search", "a.go", "--rel-cwd"},
+			filepath.FromSlash("pkg/a.go") + "\n"},
+		{"git-relative search", filepath.Join(repo, "sub"), []string{"pkg", "--search", "a.go", "--git-relative"},
+			filepath.FromSlash("sub/pkg/a.go") + "\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(bin, tc.args...)
+			cmd.Dir = tc.dir
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("treego %v: %v", tc.args, err)
+			}
+			if string(out) != tc.want {
+				t.Errorf("treego %v: expected %q, got %q", tc.args, tc.want, out)
+			}
+		})
+	}
+}
+
+func TestCLIRenderFailureExitStatus(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("no /dev/full")
+	}
+	bin := buildCLI(t)
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	var stderr strings.Builder
+	cmd := exec.Command(bin, tmpDir, "--output", "/dev/full")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Fatalf("Expected exit status 1, got %v", err)
+	}
+	if len(out) != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", out)
+	}
+	if !strings.HasPrefix(stderr.String(), "Render failed:") {
+		t.Errorf("Expected the error on stderr, got %q", stderr.String())
+	}
+}
+
+func TestCLITruncationNoticeOnFailedCheck(t *testing.T) {
+	bin := buildCLI(t)
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.Symlink("missing-"+name, filepath.Join(dir, name)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	var stderr strings.Builder
+	cmd := exec.Command(bin, dir, "--broken-links", "--max-output-bytes", "10")
+	cmd.Stderr = &stderr
+	_, err := cmd.Output()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Fatalf("Expected exit status 1, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "output truncated after 10 bytes") {
+		t.Errorf("Expected the truncation notice on stderr, got %q", stderr.String())
+	}
+}
+
+func TestCLIBoxTitleIsRootLine(t *testing.T) {
+	bin := buildCLI(t)
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	out, err := exec.Command(bin, tmpDir, "--box", "--color", "never").Output()
+	if err != nil {
+		t.Fatalf("treego --box: %v", err)
+	}
+	lines := strings.Split(string(out), "\n")
+	if !strings.HasPrefix(lines[0], "┌─ "+tmpDir+" ") {
+		t.Errorf("Expected the root path as the title, got %q", lines[0])
+	}
+	if n := strings.Count(string(out), tmpDir); n != 1 {
+		t.Errorf("Expected the root path once, got %d times:\n%s", n, out)
+	}
+}
+
+func TestCLIBoxWithColors(t *testing.T) {
+	bin := buildCLI(t)
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	// stdout is a pipe, which --color=always colors.
+	out, err := exec.Command(bin, tmpDir, "--box", "--color", "always").Output()
+	if err != nil {
+		t.Fatalf("treego --box: %v", err)
+	}
+	if !strings.Contains(string(out), "\x1b[") {
+		t.Fatalf("Expected colored output, got %q", out)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	width := treego.DisplayWidth(lines[0])
+	for _, l := range lines {
+		if treego.DisplayWidth(l) != width {
+			t.Errorf("Expected every line %d cells wide, got %d for %q", width, treego.DisplayWidth(l), l)
+		}
+	}
+}
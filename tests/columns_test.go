@@ -0,0 +1,55 @@
+package treego_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestParseColumns(t *testing.T) {
+	cols, err := treego.ParseColumns("size, mtime")
+	if err != nil || strings.Join(cols, ",") != "size,mtime,name" {
+		t.Errorf("Expected name to be appended, got %v, %v", cols, err)
+	}
+	cols, err = treego.ParseColumns("name,perms")
+	if err != nil || strings.Join(cols, ",") != "name,perms" {
+		t.Errorf("Expected the given order, got %v, %v", cols, err)
+	}
+	for _, spec := range []string{"size,bogus", "size,size"} {
+		if _, err := treego.ParseColumns(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestColumnsTable(t *testing.T) {
+	mtime := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	root := &treego.Node{Name: "root", IsDir: true, Mode: os.ModeDir | 0o755, ModTime: mtime, Children: []*treego.Node{
+		{Name: "src", IsDir: true, Mode: os.ModeDir | 0o755, ModTime: mtime, Children: []*treego.Node{
+			{Name: "main.go", Size: 1500, Mode: 0o644, ModTime: mtime},
+		}},
+		{Name: "run.sh", Size: 12, Mode: 0o755, ModTime: mtime},
+	}}
+
+	out := renderTreeOf(t, root, treego.Options{Columns: []string{"perms", "size", "name"}, Breadcrumbs: 1})
+	want := "drwxr-xr-x           root\n" +
+		"                     ── src ──\n" +
+		"drwxr-xr-x           ├── src\n" +
+		"-rw-r--r--  1.5 KiB  │   └── main.go\n" +
+		"-rwxr-xr-x     12 B  └── run.sh\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	out = renderTreeOf(t, root, treego.Options{Columns: []string{"name", "mtime"}, ShowSize: true})
+	want = "root             2024-05-01 09:30\n" +
+		"├── src          2024-05-01 09:30\n" +
+		"│   └── main.go  2024-05-01 09:30\n" +
+		"└── run.sh       2024-05-01 09:30\n"
+	if out != want {
+		t.Errorf("Expected trailing columns aligned and --size replaced, got:\n%s", out)
+	}
+}
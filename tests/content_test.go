@@ -0,0 +1,111 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestContentLimitAllows(t *testing.T) {
+	l := treego.ContentLimit{Extensions: []string{"go", ".MD"}, MaxSize: 100}
+	for _, tc := range []struct {
+		node treego.Node
+		want bool
+	}{
+		{treego.Node{Name: "main.go", Size: 10}, true},
+		{treego.Node{Name: "README.md", Size: 100}, true},
+		{treego.Node{Name: "big.go", Size: 101}, false},
+		{treego.Node{Name: "notes.txt", Size: 1}, false},
+		{treego.Node{Name: "Makefile", Size: 1}, false},
+	} {
+		if got := l.Allows(&tc.node); got != tc.want {
+			t.Errorf("Allows(%s, %d) = %v, want %v", tc.node.Name, tc.node.Size, got, tc.want)
+		}
+	}
+	if !(treego.ContentLimit{}).Allows(&treego.Node{Name: "any", Size: 1 << 40}) {
+		t.Error("Expected the zero ContentLimit to allow every file")
+	}
+}
+
+func TestLimitContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":      "package main // TODO\n",
+		"notes.txt":    "TODO later\n",
+		"gen/large.go": "package gen // TODO " + strings.Repeat("x", 200) + "\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resetGlobalState()
+	root := treego.BuildTreeSafe(dir)
+	var manifest bytes.Buffer
+	if err := treego.WriteManifest(root, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	fullHash, err := treego.TreeHash(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treego.LimitContent(root, treego.ContentLimit{Extensions: []string{"go"}, MaxSize: 100})
+	if got := matchedNames(treego.SearchContent(root, treego.MatchQuery{Content: regexp.MustCompile("TODO")})); strings.Join(got, ",") != "main.go" {
+		t.Errorf("Expected grep to read only main.go, got %v", got)
+	}
+
+	if err := treego.HashFiles(root); err != nil {
+		t.Fatal(err)
+	}
+	treego.AttachPreviews(root, 1)
+	for _, f := range root.Children {
+		if f.IsDir {
+			f = f.Children[0]
+		}
+		if read := f.Name == "main.go"; (f.Hash != "") != read || (f.Preview != nil) != read {
+			t.Errorf("%s: hash %q, preview %q; expected only main.go to be read", f.Name, f.Hash, f.Preview)
+		}
+	}
+
+	var limited bytes.Buffer
+	if err := treego.WriteManifest(root, &limited); err != nil {
+		t.Fatal(err)
+	}
+	if out := limited.String(); !strings.HasSuffix(out, "  main.go\n") || strings.Count(out, "\n") != 1 {
+		t.Errorf("Expected only main.go in the manifest, got:\n%s", out)
+	}
+	full, err := treego.ReadManifest(&manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full["notes.txt"] = strings.Repeat("0", 64)
+	if v := treego.VerifyManifest(root, full); !v.OK() {
+		t.Errorf("Expected skipped files to be ignored by verification, got %+v", v)
+	}
+
+	limitedHash, err := treego.TreeHash(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limitedHash == fullHash {
+		t.Error("Expected skipped files to hash by size rather than content")
+	}
+	if again, _ := treego.TreeHash(root); again != limitedHash {
+		t.Errorf("Expected a stable hash, got %s then %s", limitedHash, again)
+	}
+
+	treego.LimitContent(root, treego.ContentLimit{})
+	if got := treego.SearchContent(root, treego.MatchQuery{Content: regexp.MustCompile("TODO")}); len(got) != 3 {
+		t.Errorf("Expected every file read after lifting the limit, got %v", matchedNames(got))
+	}
+}
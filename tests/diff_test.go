@@ -0,0 +1,170 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestDiffTrees(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	resetGlobalState()
+	before := treego.BuildTreeSafe(tmpDir)
+	if before == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	t.Run("identical trees have no delta", func(t *testing.T) {
+		resetGlobalState()
+		if d := treego.DiffTrees(before, treego.BuildTreeSafe(tmpDir)); d != nil {
+			t.Errorf("Expected nil delta, got %+v", d)
+		}
+	})
+
+	os.Remove(filepath.Join(tmpDir, "file1.txt"))
+	os.WriteFile(filepath.Join(tmpDir, "dir1", "subdir1", "file4.go"), []byte("much longer content"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "dir3"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "dir3", "new.txt"), []byte("new"), 0644)
+
+	resetGlobalState()
+	after := treego.BuildTreeSafe(tmpDir)
+	d := treego.DiffTrees(before, after)
+	if d == nil {
+		t.Fatal("Expected a delta")
+	}
+
+	t.Run("counts changes", func(t *testing.T) {
+		added, removed, modified, renamed := treego.DeltaCounts(d)
+		if added != 2 || removed != 1 || modified != 1 || renamed != 0 {
+			t.Errorf("Expected 2 added, 1 removed, 1 modified, 0 renamed; got %d, %d, %d, %d", added, removed, modified, renamed)
+		}
+	})
+
+	t.Run("prints pruned annotated tree", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintDelta(&buf, d, treego.Options{RootLabel: "root"}); err != nil {
+			t.Fatalf("PrintDelta failed: %v", err)
+		}
+		want := "root\n" +
+			"├── dir1\n" +
+			"│   └── subdir1\n" +
+			"│       └── [~] file4.go\n" +
+			"├── [+] dir3\n" +
+			"│   └── [+] new.txt\n" +
+			"└── [-] file1.txt\n"
+		if buf.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+		}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	resetGlobalState()
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	state := filepath.Join(t.TempDir(), "state.json")
+	if _, err := treego.LoadSnapshot(state); !os.IsNotExist(err) {
+		t.Fatalf("Expected not-exist error for missing snapshot, got %v", err)
+	}
+	if err := treego.SaveSnapshot(state, root); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	loaded, err := treego.LoadSnapshot(state)
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	if d := treego.DiffTrees(loaded, root); d != nil {
+		t.Errorf("Expected loaded snapshot to match, got delta %+v", d)
+	}
+
+	// A later modification time alone counts as a change.
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(tmpDir, "file2.go"), later, later)
+	resetGlobalState()
+	if _, _, modified, _ := treego.DeltaCounts(treego.DiffTrees(loaded, treego.BuildTreeSafe(tmpDir))); modified != 1 {
+		t.Errorf("Expected mtime change to be detected, got %d modified", modified)
+	}
+}
+
+func TestDetectRenames(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "docs", IsDir: true, Children: []*treego.Node{
+			{Name: "guide.md", Size: 10, Hash: "aaa"},
+		}},
+		{Name: "Readme.md", Size: 5, ModTime: mtime},
+		{Name: "old.txt", Size: 3, Hash: "ccc"},
+	}}
+	after := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "docs", IsDir: true},
+		{Name: "manual", IsDir: true, Children: []*treego.Node{
+			{Name: "guide.md", Size: 10, Hash: "aaa"},
+		}},
+		{Name: "README.md", Size: 5, ModTime: mtime},
+		{Name: "new.txt", Size: 3, Hash: "ddd"},
+	}}
+
+	d := treego.DetectRenames(treego.DiffTrees(before, after))
+	var buf bytes.Buffer
+	if err := treego.PrintDelta(&buf, d, treego.Options{RootLabel: "root"}); err != nil {
+		t.Fatalf("PrintDelta failed: %v", err)
+	}
+	want := "root\n" +
+		"├── [+] manual\n" +
+		"│   └── [>] guide.md (from docs/guide.md)\n" +
+		"├── [+] new.txt\n" +
+		"├── [-] old.txt\n" +
+		"└── [>] README.md (from Readme.md)\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+	if added, removed, modified, renamed := treego.DeltaCounts(d); added != 2 || removed != 1 || modified != 0 || renamed != 2 {
+		t.Errorf("Expected 2 added, 1 removed, 2 renamed; got %d, %d, %d, %d", added, removed, modified, renamed)
+	}
+
+	if treego.DetectRenames(nil) != nil {
+		t.Error("Expected nil for an empty delta")
+	}
+}
+
+func TestHashFiles(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+	// Links are not opened: a directory or dangling link would fail.
+	for link, target := range map[string]string{"dirlink": "dir1", "dangling": "missing"} {
+		if err := os.Symlink(target, filepath.Join(tmpDir, link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+	resetGlobalState()
+	root := treego.BuildTreeSafe(tmpDir)
+	if err := treego.HashFiles(root); err != nil {
+		t.Fatalf("HashFiles failed: %v", err)
+	}
+	var walk func(n *treego.Node)
+	walk = func(n *treego.Node) {
+		want := ""
+		if !n.IsDir && n.Mode.IsRegular() {
+			want, _ = treego.HashFile(n.Path)
+		}
+		if n.Hash != want {
+			t.Errorf("Expected hash %q on %s, got %q", want, n.Path, n.Hash)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+}
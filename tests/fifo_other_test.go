@@ -0,0 +1,11 @@
+//go:build !unix
+
+package treego_test
+
+import "testing"
+
+// mkfifo skips the test: named pipes are Unix only.
+func mkfifo(t *testing.T, path string) {
+	t.Helper()
+	t.Skip("named pipes are Unix only")
+}
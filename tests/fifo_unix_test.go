@@ -0,0 +1,16 @@
+//go:build unix
+
+package treego_test
+
+import (
+	"syscall"
+	"testing"
+)
+
+// mkfifo creates a named pipe at path, skipping the test when that fails.
+func mkfifo(t *testing.T, path string) {
+	t.Helper()
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Skipf("mkfifo unsupported: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package treego_test
+
+import (
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestGlobMatch(t *testing.T) {
+	cases := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/c/main.go", true},
+		{"**/*.go", "a/b/notes.txt", false},
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"cmd/**/main.go", "cmd/main.go", true},
+		{"cmd/**/main.go", "cmd/treego/x/main.go", true},
+		{"cmd/**/main.go", "pkg/cmd/main.go", false},
+		{"src/**", "src/a/b", true},
+		{"**/test?/**/*.txt", "x/test1/y/z.txt", true},
+		{"**/**/[ab].txt", "deep/b.txt", true},
+	}
+	for _, c := range cases {
+		g, err := treego.CompileGlob(c.pattern)
+		if err != nil {
+			t.Fatalf("CompileGlob(%q) failed: %v", c.pattern, err)
+		}
+		if got := g.Match(c.rel); got != c.want {
+			t.Errorf("%q.Match(%q) = %v; want %v", c.pattern, c.rel, got, c.want)
+		}
+	}
+	for _, bad := range []string{"a//b", "[", "**/[x"} {
+		if _, err := treego.CompileGlob(bad); err == nil {
+			t.Errorf("CompileGlob(%q) succeeded; want an error", bad)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	g, _ := treego.CompileGlob("**/*.go")
+	out := renderTreeOf(t, treego.Find(root, []*treego.Glob{g}), treego.Options{RootLabel: "root"})
+	want := "root\n├── dir1\n│   └── subdir1\n│       └── file4.go\n└── file2.go\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	g, _ = treego.CompileGlob("**/*.rs")
+	if treego.Find(root, []*treego.Glob{g}) != nil {
+		t.Error("Expected nil when nothing matches")
+	}
+}
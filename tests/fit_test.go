@@ -0,0 +1,67 @@
+package treego_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+// wideTree has dirs directories of files files each, plus one nested
+// directory in the first.
+func wideTree(dirs, files int) *treego.Node {
+	root := &treego.Node{Name: "root", IsDir: true}
+	for d := 0; d < dirs; d++ {
+		dir := &treego.Node{Name: fmt.Sprintf("d%d", d), IsDir: true}
+		for f := 0; f < files; f++ {
+			dir.Children = append(dir.Children, &treego.Node{Name: fmt.Sprintf("f%d", f)})
+		}
+		root.Children = append(root.Children, dir)
+	}
+	root.Children[0].Children = append(root.Children[0].Children, &treego.Node{Name: "deep", IsDir: true, Children: []*treego.Node{{Name: "x"}}})
+	return root
+}
+
+func TestFitDepth(t *testing.T) {
+	// 1 root + 3 dirs + 15 files + deep + x = 21 lines at full depth.
+	root := wideTree(3, 5)
+	cases := []struct {
+		lines, depth, hidden int
+	}{
+		{21, 0, 0},
+		{20, 2, 1},
+		{19, 1, 17},
+		{2, 1, 17},
+	}
+	for _, c := range cases {
+		depth, hidden, err := treego.FitDepth(root, treego.Options{}, c.lines)
+		if err != nil || depth != c.depth || hidden != c.hidden {
+			t.Errorf("FitDepth(%d lines) = %d, %d, %v; want %d, %d", c.lines, depth, hidden, err, c.depth, c.hidden)
+		}
+	}
+}
+
+func TestFitScreen(t *testing.T) {
+	var buf bytes.Buffer
+	if err := treego.FitScreen(&buf, wideTree(3, 5), treego.Options{}, 6); err != nil {
+		t.Fatalf("FitScreen failed: %v", err)
+	}
+	want := "root\n├── d0\n├── d1\n└── d2\n... 17 entries below depth 1 collapsed to fit the screen\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := treego.FitScreen(&buf, wideTree(3, 5), treego.Options{}, 0); err != nil {
+		t.Fatalf("FitScreen failed: %v", err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 21 || strings.Contains(buf.String(), "collapsed") {
+		t.Errorf("Expected the full tree when the height is unknown, got:\n%s", buf.String())
+	}
+
+	if treego.TerminalHeight(&buf) != 0 {
+		t.Error("Expected no height for a buffer")
+	}
+}
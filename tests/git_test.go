@@ -0,0 +1,254 @@
+package treego_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestFindRepoRoot(t *testing.T) {
+	repo := t.TempDir()
+	nested := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(nested, "main.go")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{repo, nested, file} {
+		got, err := treego.FindRepoRoot(start)
+		if err != nil || got != repo {
+			t.Errorf("FindRepoRoot(%s) = %q, %v; want %q", start, got, err, repo)
+		}
+	}
+
+	t.Run("gitfile marks a worktree", func(t *testing.T) {
+		wt := t.TempDir()
+		if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: elsewhere\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := treego.FindRepoRoot(wt); err != nil || got != wt {
+			t.Errorf("FindRepoRoot(worktree) = %q, %v; want %q", got, err, wt)
+		}
+	})
+
+	t.Run("outside a repository", func(t *testing.T) {
+		// The temp dir may itself live inside a repository on some machines.
+		outside := t.TempDir()
+		if _, err := treego.FindRepoRoot(filepath.Dir(outside)); err == nil {
+			t.Skip("temp dir is inside a git repository")
+		}
+		if _, err := treego.FindRepoRoot(outside); !errors.Is(err, treego.ErrNotInRepo) {
+			t.Errorf("Expected ErrNotInRepo, got %v", err)
+		}
+	})
+}
+
+const gitmodules = `# comment
+[submodule "vendor/lib"]
+	path = vendor/lib/
+	url = https://example.com/lib.git
+[core]
+	bare = false
+[submodule "docs"]
+	path = docs
+	url = "git@example.com:docs.git"
+`
+
+func TestParseGitmodules(t *testing.T) {
+	subs, err := treego.ParseGitmodules(strings.NewReader(gitmodules))
+	if err != nil {
+		t.Fatalf("ParseGitmodules failed: %v", err)
+	}
+	want := []treego.Submodule{
+		{Name: "docs", Path: "docs", URL: "git@example.com:docs.git"},
+		{Name: "vendor/lib", Path: "vendor/lib", URL: "https://example.com/lib.git"},
+	}
+	if len(subs) != len(want) {
+		t.Fatalf("Expected %d submodules, got %+v", len(want), subs)
+	}
+	for i := range want {
+		if subs[i] != want[i] {
+			t.Errorf("subs[%d] = %+v; want %+v", i, subs[i], want[i])
+		}
+	}
+
+	if _, err := treego.ParseGitmodules(strings.NewReader("[submodule \"x\"]\n\tpath\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected malformed-line error, got %v", err)
+	}
+}
+
+func TestSubmoduleDirs(t *testing.T) {
+	repo := t.TempDir()
+	for _, dir := range []string{".git", "docs", "vendor/lib", "src"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, subs, err := treego.LoadSubmodules(filepath.Join(repo, "vendor"))
+	if err != nil || got != repo || len(subs) != 2 {
+		t.Fatalf("LoadSubmodules = %q, %+v, %v", got, subs, err)
+	}
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(filepath.Join(repo, "vendor"), treego.Options{})
+	found := treego.SubmoduleDirs(root, repo, subs)
+	if len(found) != 1 || found[0].Path != "vendor/lib" {
+		t.Fatalf("Expected only vendor/lib under vendor, got %+v", found)
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintSubmodules(&buf, found); err != nil {
+		t.Fatalf("PrintSubmodules failed: %v", err)
+	}
+	if buf.String() != "vendor/lib  https://example.com/lib.git\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+
+	t.Run("missing .gitmodules", func(t *testing.T) {
+		bare := t.TempDir()
+		if err := os.Mkdir(filepath.Join(bare, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if _, subs, err := treego.LoadSubmodules(bare); err != nil || subs != nil {
+			t.Errorf("Expected no submodules, got %+v, %v", subs, err)
+		}
+	})
+}
+
+func TestAnnotateGitAuthors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	date := "2024-01-01T12:00:00Z"
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Ada", "-c", "user.email=ada@example.com"}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	// A name that is also a glob: its history must not include main.go's.
+	glob := filepath.Join(repo, "src", "*.go")
+	if err := os.MkdirAll(filepath.Dir(glob), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(glob, nil, 0644); err != nil {
+		t.Skipf("cannot create %s: %v", glob, err)
+	}
+	git("add", "src/*.go")
+	git("commit", "-q", "-m", "glob")
+	date = "2024-05-01T12:00:00Z"
+	for _, f := range []string{"src/main.go", "src/new.go", "ignored.log", ".gitignore"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repo, f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, f), []byte("*.log\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("add", "src/main.go", ".gitignore")
+	git("commit", "-q", "-m", "initial")
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(filepath.Join(repo, "src"), treego.Options{})
+	if err := treego.AnnotateGitAuthors(root, repo); err != nil {
+		t.Fatalf("AnnotateGitAuthors failed: %v", err)
+	}
+	for _, c := range root.Children {
+		switch c.Name {
+		case "main.go":
+			if c.LastCommit == nil || c.LastCommit.Author != "Ada" || !c.LastCommit.Date.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+				t.Errorf("Unexpected commit for main.go: %+v", c.LastCommit)
+			}
+		case "*.go":
+			if c.LastCommit == nil || !c.LastCommit.Date.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+				t.Errorf("Unexpected commit for *.go: %+v", c.LastCommit)
+			}
+		case "new.go":
+			if c.LastCommit != nil {
+				t.Errorf("Expected untracked new.go to stay plain, got %+v", c.LastCommit)
+			}
+		}
+	}
+
+	out := renderTreeOf(t, root, treego.Options{ShowGitAuthor: true})
+	if !strings.Contains(out, "main.go  (Ada, 2024-05-01)\n") || !strings.Contains(out, "new.go\n") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// The temp dir may itself live inside a repository on some machines.
+	outside := t.TempDir()
+	if _, err := treego.FindRepoRoot(outside); err != nil {
+		if err := treego.AnnotateGitAuthors(root, outside); err == nil {
+			t.Error("Expected an error outside a repository")
+		}
+	}
+}
+
+func TestFindGitLargeFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	sizes := map[string]int{".gitignore": 6, "big.bin": 200, "mid.bin": 50, "small.txt": 5, "huge.log": 500}
+	for name, size := range sizes {
+		content := strings.Repeat("x", size)
+		if name == ".gitignore" {
+			content = "*.log\n"
+		}
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(repo, treego.Options{})
+	large, err := treego.FindGitLargeFiles(root, repo, 10, 100)
+	if err != nil {
+		t.Fatalf("FindGitLargeFiles failed: %v", err)
+	}
+	want := []treego.LargeFile{
+		{Path: filepath.Join(repo, "big.bin"), Size: 200, OverLimit: true},
+		{Path: filepath.Join(repo, "mid.bin"), Size: 50},
+	}
+	if !reflect.DeepEqual(large, want) {
+		t.Errorf("Expected %+v, got %+v", want, large)
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintGitLargeFiles(&buf, large, 10, 100); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "error         200 B  ") || !strings.HasSuffix(out, "2 files larger than 10 B, 1 over the 100 B limit\n") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+	buf.Reset()
+	treego.PrintGitLargeFiles(&buf, nil, treego.GitLargeFileWarn, treego.GitLargeFileLimit)
+	if buf.String() != "No files larger than 50.0 MiB\n" {
+		t.Errorf("Unexpected output for no files: %q", buf.String())
+	}
+}
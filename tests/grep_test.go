@@ -0,0 +1,118 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+// Helper function to create files with specific contents for content matching tests
+func createContentDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for name, content := range files {
+		filePath := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+	return tmpDir
+}
+
+func matchedNames(matches []treego.Match) []string {
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.Node.Name)
+	}
+	return names
+}
+
+func TestSearchContent(t *testing.T) {
+	tmpDir := createContentDir(t, map[string]string{
+		"config.yml":       "name: app\nlegacy_timeout: 5\n",
+		"config.json":      "{\"timeout\": 5}\n",
+		"src/notes.txt":    "remember legacy_timeout\n",
+		"bin/config.bin":   "legacy_timeout\x00\x01",
+		"src/other/x.conf": "nothing here\n",
+	})
+	resetGlobalState()
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+	content := regexp.MustCompile(`legacy_timeout`)
+
+	t.Run("name AND content", func(t *testing.T) {
+		matches := treego.SearchContent(root, treego.MatchQuery{Name: "config", Content: content})
+		names := matchedNames(matches)
+		if len(names) != 1 || names[0] != "config.yml" {
+			t.Fatalf("Expected only config.yml, got %v", names)
+		}
+		if len(matches[0].Lines) != 1 || matches[0].Lines[0].Line != 2 {
+			t.Errorf("Expected match on line 2, got %+v", matches[0].Lines)
+		}
+	})
+
+	t.Run("name OR content", func(t *testing.T) {
+		names := matchedNames(treego.SearchContent(root, treego.MatchQuery{Name: "config", Content: content, Any: true}))
+		joined := strings.Join(names, ",")
+		for _, want := range []string{"config.yml", "config.json", "config.bin", "notes.txt"} {
+			if !strings.Contains(joined, want) {
+				t.Errorf("Expected %s in OR results, got %v", want, names)
+			}
+		}
+		if strings.Contains(joined, "x.conf") {
+			t.Errorf("Did not expect x.conf in OR results, got %v", names)
+		}
+	})
+
+	t.Run("content only skips binary files", func(t *testing.T) {
+		names := matchedNames(treego.SearchContent(root, treego.MatchQuery{Content: content}))
+		joined := strings.Join(names, ",")
+		if !strings.Contains(joined, "config.yml") || !strings.Contains(joined, "notes.txt") {
+			t.Errorf("Expected text matches, got %v", names)
+		}
+		if strings.Contains(joined, "config.bin") {
+			t.Errorf("Expected binary file to be skipped, got %v", names)
+		}
+	})
+
+	t.Run("special files are not read", func(t *testing.T) {
+		dir := createContentDir(t, map[string]string{"a.txt": "legacy_timeout\n"})
+		// Opening a FIFO would block until a writer shows up.
+		mkfifo(t, filepath.Join(dir, "legacy.pipe"))
+		resetGlobalState()
+		root := treego.BuildTreeSafe(dir)
+		var contentOnly, nameOrContent []string
+		withinTimeout(t, func() {
+			contentOnly = matchedNames(treego.SearchContent(root, treego.MatchQuery{Content: content}))
+			nameOrContent = matchedNames(treego.SearchContent(root, treego.MatchQuery{Name: "legacy", Content: content, Any: true}))
+		})
+		if strings.Join(contentOnly, ",") != "a.txt" {
+			t.Errorf("Expected only a.txt, got %v", contentOnly)
+		}
+		if joined := strings.Join(nameOrContent, ","); !strings.Contains(joined, "a.txt") || !strings.Contains(joined, "legacy.pipe") {
+			t.Errorf("Expected the FIFO to still match by name, got %v", nameOrContent)
+		}
+	})
+
+	t.Run("print matches", func(t *testing.T) {
+		var buf bytes.Buffer
+		matches := treego.SearchContent(root, treego.MatchQuery{Name: "config", Content: content})
+		if err := treego.PrintMatches(&buf, matches); err != nil {
+			t.Fatalf("PrintMatches failed: %v", err)
+		}
+		want := filepath.Join(tmpDir, "config.yml") + "\n  2: legacy_timeout: 5\n"
+		if buf.String() != want {
+			t.Errorf("Expected %q, got %q", want, buf.String())
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package treego_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestStableID(t *testing.T) {
+	t.Run("deterministic and separator independent", func(t *testing.T) {
+		a := treego.StableID("src/main.go")
+		if a != treego.StableID("src/main.go") {
+			t.Error("Expected StableID to be deterministic")
+		}
+		if a != treego.StableID("src/./main.go") {
+			t.Error("Expected StableID to clean the path")
+		}
+		if a == treego.StableID("src/main.go2") {
+			t.Error("Expected different paths to get different IDs")
+		}
+		if len(a) != 16 {
+			t.Errorf("Expected 16 character ID, got %q", a)
+		}
+	})
+
+	t.Run("IDs are unique across a scanned tree and stable across scans", func(t *testing.T) {
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		resetGlobalState()
+		first := treego.BuildTreeSafe(tmpDir)
+		second := treego.BuildTreeSafe(tmpDir)
+		if first == nil || second == nil {
+			t.Fatal("Failed to build tree")
+		}
+		treego.AssignStableIDs(first)
+		treego.AssignStableIDs(second)
+
+		ids := map[string]string{}
+		var walk func(n *treego.Node)
+		walk = func(n *treego.Node) {
+			if prev, dup := ids[n.ID]; dup {
+				t.Errorf("Duplicate ID %s for %s and %s", n.ID, prev, n.Path)
+			}
+			ids[n.ID] = n.Path
+			for _, c := range n.Children {
+				walk(c)
+			}
+		}
+		walk(first)
+
+		var compare func(a, b *treego.Node)
+		compare = func(a, b *treego.Node) {
+			if a.ID != b.ID {
+				t.Errorf("Expected same ID for %s across scans", a.Path)
+			}
+			for i := range a.Children {
+				compare(a.Children[i], b.Children[i])
+			}
+		}
+		compare(first, second)
+
+		if first.ID != treego.StableID(".") {
+			t.Error("Expected root ID to derive from \".\"")
+		}
+	})
+}
+
+func TestNDJSONRenderer(t *testing.T) {
+	root := sampleTree()
+	treego.AssignStableIDs(root)
+
+	var buf bytes.Buffer
+	if err := treego.Render(root, &buf, "ndjson", treego.Options{}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	type record struct {
+		ID       string `json:"id"`
+		ParentID string `json:"parentId"`
+		Name     string `json:"name"`
+	}
+	var records []record
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var r record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", sc.Text(), err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(records))
+	}
+	if records[0].ParentID != "" || records[1].ParentID != records[0].ID || records[2].ParentID != records[1].ID {
+		t.Errorf("Expected parent IDs to link records, got %+v", records)
+	}
+	if records[2].ID != treego.StableID("src/main.go") {
+		t.Errorf("Expected main.go ID from its relative path, got %s", records[2].ID)
+	}
+}
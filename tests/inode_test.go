@@ -0,0 +1,51 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestCountInodes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inode numbers are not available on Windows")
+	}
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	original := filepath.Join(tmpDir, "file1.txt")
+	if err := os.WriteFile(original, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(original, filepath.Join(tmpDir, "dir2", "link.txt")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	root := treego.BuildFilteredTree(tmpDir, treego.Options{})
+	s := treego.CountInodes(root)
+	if s.Dirs != 5 || s.Files != 7 {
+		t.Fatalf("Expected 5 dirs and 7 files, got %+v", s)
+	}
+	if s.HardLinks != 1 || s.Inodes != 11 {
+		t.Errorf("Expected 11 inodes with 1 shared link, got %+v", s)
+	}
+	// Five 12-byte fixtures plus the 5-byte file; the link adds nothing.
+	if s.Bytes != 65 {
+		t.Errorf("Expected linked bytes counted once (65), got %d", s.Bytes)
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintInodeSummary(&buf, s, treego.Options{}); err != nil {
+		t.Fatalf("PrintInodeSummary failed: %v", err)
+	}
+	want := "Size:   65 B in 7 files\nInodes: 11 (5 directories, 7 files; 1 hard links counted once)\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
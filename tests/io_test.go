@@ -0,0 +1,61 @@
+package treego_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestIOProfiles(t *testing.T) {
+	for _, name := range []string{"auto", "ssd", "hdd", "network"} {
+		p, err := treego.ParseIOProfile(name)
+		if err != nil || p.String() != name {
+			t.Errorf("ParseIOProfile(%q) = %v, %v", name, p, err)
+		}
+	}
+	if _, err := treego.ParseIOProfile("tape"); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+	if !(treego.IOHDD.Workers() < treego.IONetwork.Workers() && treego.IONetwork.Workers() < treego.IOSSD.Workers()) {
+		t.Errorf("Expected hdd < network < ssd workers, got %d, %d, %d", treego.IOHDD.Workers(), treego.IONetwork.Workers(), treego.IOSSD.Workers())
+	}
+	if p := treego.DetectIOProfile(t.TempDir()); p == treego.IOAuto {
+		t.Error("Expected detection to settle on a concrete profile")
+	}
+}
+
+func TestScanWithIOSettings(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	resetGlobalState()
+	wantDirs, wantFiles := countNodes(treego.BuildTreeSafe(tmpDir))
+	for _, opts := range []treego.Options{{IO: treego.IOHDD}, {IO: treego.IONetwork}, {Workers: 1}} {
+		resetGlobalState()
+		dirs, files := countNodes(treego.BuildFilteredTree(tmpDir, opts))
+		if dirs != wantDirs || files != wantFiles {
+			t.Errorf("With %+v: got %d dirs and %d files; want %d and %d", opts, dirs, files, wantDirs, wantFiles)
+		}
+	}
+}
+
+func TestIOWorkersClamp(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	for _, tc := range []struct{ procs, want int }{{1, 32}, {4, 64}, {64, 512}} {
+		runtime.GOMAXPROCS(tc.procs)
+		if got := treego.IOSSD.Workers(); got != tc.want {
+			t.Errorf("GOMAXPROCS=%d: IOSSD.Workers() = %d, want %d", tc.procs, got, tc.want)
+		}
+		if got := treego.IOAuto.Workers(); got != tc.want {
+			t.Errorf("GOMAXPROCS=%d: IOAuto.Workers() = %d, want %d", tc.procs, got, tc.want)
+		}
+	}
+	if got := treego.IOHDD.Workers(); got != 4 {
+		t.Errorf("IOHDD.Workers() = %d, want 4", got)
+	}
+	if got := treego.IONetwork.Workers(); got != 16 {
+		t.Errorf("IONetwork.Workers() = %d, want 16", got)
+	}
+}
@@ -0,0 +1,74 @@
+package treego_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestLimitWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lw := treego.NewLimitWriter(&buf, 10)
+	if _, err := lw.Write([]byte("abc\n")); err != nil || lw.Exceeded() {
+		t.Fatalf("Expected first write to pass, got %v", err)
+	}
+	n, err := lw.Write([]byte("de\nfghij\n"))
+	if !errors.Is(err, treego.ErrOutputLimit) || n != 3 || !lw.Exceeded() {
+		t.Errorf("Write = %d, %v; want 3, ErrOutputLimit", n, err)
+	}
+	if _, err := lw.Write([]byte("x")); !errors.Is(err, treego.ErrOutputLimit) {
+		t.Errorf("Expected later writes to fail, got %v", err)
+	}
+	if buf.String() != "abc\nde\n" {
+		t.Errorf("Expected output cut at the last whole line, got %q", buf.String())
+	}
+}
+
+func TestLimitWriterAcrossFormats(t *testing.T) {
+	for _, format := range []string{"tree", "json", "csv", "yaml"} {
+		var full bytes.Buffer
+		if err := treego.Render(sampleTree(), &full, format, treego.Options{}); err != nil {
+			t.Fatalf("Render(%s) failed: %v", format, err)
+		}
+		var buf bytes.Buffer
+		err := treego.Render(sampleTree(), treego.NewLimitWriter(&buf, 30), format, treego.Options{})
+		if !errors.Is(err, treego.ErrOutputLimit) {
+			t.Errorf("%s: expected ErrOutputLimit, got %v", format, err)
+		}
+		if buf.Len() > 30 || !strings.HasPrefix(full.String(), buf.String()) {
+			t.Errorf("%s: expected a prefix of at most 30 bytes, got %q", format, buf.String())
+		}
+	}
+}
+
+func TestLimitWriterHoldsPartialLines(t *testing.T) {
+	var buf bytes.Buffer
+	lw := treego.NewLimitWriter(&buf, 10)
+	for _, s := range []string{"ab", "c\nde", "f"} {
+		if _, err := lw.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q) failed: %v", s, err)
+		}
+	}
+	if buf.String() != "abc\n" {
+		t.Errorf("Expected only the finished line to be written, got %q", buf.String())
+	}
+	if _, err := lw.Write([]byte("ghijk\n")); !errors.Is(err, treego.ErrOutputLimit) {
+		t.Errorf("Expected ErrOutputLimit, got %v", err)
+	}
+	if err := lw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "abc\n" {
+		t.Errorf("Expected the cut line to be dropped, got %q", buf.String())
+	}
+
+	buf.Reset()
+	lw = treego.NewLimitWriter(&buf, 10)
+	lw.Write([]byte("ab\ncd"))
+	if err := lw.Flush(); err != nil || buf.String() != "ab\ncd" {
+		t.Errorf("Expected Flush to write the last line, got %q, %v", buf.String(), err)
+	}
+}
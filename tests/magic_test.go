@@ -0,0 +1,77 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestMatchSignature(t *testing.T) {
+	tarHead := make([]byte, 262)
+	copy(tarHead[257:], "ustar")
+	cases := map[string][]byte{
+		"PNG":  []byte("\x89PNG\r\n\x1a\n\x00\x00"),
+		"JPEG": {0xff, 0xd8, 0xff, 0xe0},
+		"ELF":  []byte("\x7fELF\x02\x01"),
+		"ZIP":  []byte("PK\x03\x04rest"),
+		"PDF":  []byte("%PDF-1.7"),
+		"WEBP": []byte("RIFF\x00\x00\x00\x00WEBPVP8 "),
+		"TAR":  tarHead,
+		"":     []byte("package main"),
+	}
+	for want, head := range cases {
+		if got := treego.MatchSignature(head); got != want {
+			t.Errorf("MatchSignature(%q) = %q; want %q", head[:min(len(head), 12)], got, want)
+		}
+	}
+	if got := treego.MatchSignature([]byte{0x89, 'P'}); got != "" {
+		t.Errorf("Expected no match for a truncated header, got %q", got)
+	}
+}
+
+func TestDetectSignatures(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"logo.jpg":  "\x89PNG\r\n\x1a\nimage data",
+		"notes.txt": "just text",
+		"empty":     "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(dir, treego.Options{})
+	treego.DetectSignatures(root)
+	for _, c := range root.Children {
+		want := ""
+		if c.Name == "logo.jpg" {
+			want = "PNG"
+		}
+		if c.Signature != want {
+			t.Errorf("%s: signature %q; want %q", c.Name, c.Signature, want)
+		}
+	}
+
+	out := renderTreeOf(t, root, treego.Options{ShowSignature: true})
+	if !strings.Contains(out, "logo.jpg [PNG]\n") || !strings.Contains(out, "notes.txt\n") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	t.Run("special files are not read", func(t *testing.T) {
+		dir := t.TempDir()
+		// Opening a FIFO would block until a writer shows up.
+		mkfifo(t, filepath.Join(dir, "pipe"))
+		resetGlobalState()
+		root := treego.BuildTreeSafe(dir)
+		withinTimeout(t, func() { treego.DetectSignatures(root) })
+		if len(root.Children) != 1 || root.Children[0].Signature != "" {
+			t.Errorf("Expected pipe without a signature, got %+v", root.Children)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/marcuwynu23/treego/treego"
 )
@@ -913,3 +914,19 @@ func BenchmarkPrintTreeDFS(b *testing.B) {
 	}
 }
 
+
+// withinTimeout runs fn and fails the test if it has not returned after a
+// few seconds, such as when it blocks opening a FIFO.
+func withinTimeout(t *testing.T, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out; a special file was probably opened")
+	}
+}
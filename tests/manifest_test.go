@@ -0,0 +1,222 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func buildManifest(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	resetGlobalState()
+	root := treego.BuildTreeSafe(dir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+	var buf bytes.Buffer
+	if err := treego.Render(root, &buf, "manifest", treego.Options{}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	manifest, err := treego.ReadManifest(&buf)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	return manifest
+}
+
+func TestManifest(t *testing.T) {
+	t.Run("manifest lists files with relative slash paths", func(t *testing.T) {
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		manifest := buildManifest(t, tmpDir)
+		if len(manifest) != 6 {
+			t.Fatalf("Expected 6 entries, got %d: %v", len(manifest), manifest)
+		}
+		sum, ok := manifest["dir1/subdir1/file4.go"]
+		if !ok {
+			t.Fatalf("Expected nested relative path in manifest, got %v", manifest)
+		}
+		want, err := treego.HashFile(filepath.Join(tmpDir, "dir1", "subdir1", "file4.go"))
+		if err != nil || sum != want {
+			t.Errorf("Expected digest %s, got %s (err %v)", want, sum, err)
+		}
+	})
+
+	t.Run("read accepts sha256sum binary marker and rejects garbage", func(t *testing.T) {
+		digest := strings.Repeat("a", 64)
+		m, err := treego.ReadManifest(strings.NewReader(digest + " *bin/app\n\n"))
+		if err != nil || m["bin/app"] != digest {
+			t.Errorf("Expected binary-mode entry, got %v (err %v)", m, err)
+		}
+		if _, err := treego.ReadManifest(strings.NewReader("not a manifest\n")); err == nil {
+			t.Error("Expected error for malformed manifest")
+		}
+	})
+
+	t.Run("verify reports missing, extra and changed files", func(t *testing.T) {
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		manifest := buildManifest(t, tmpDir)
+
+		os.Remove(filepath.Join(tmpDir, "file1.txt"))
+		os.WriteFile(filepath.Join(tmpDir, "dir2", "file5.txt"), []byte("changed"), 0644)
+		os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("new"), 0644)
+
+		resetGlobalState()
+		root := treego.BuildTreeSafe(tmpDir)
+		v := treego.VerifyManifest(root, manifest)
+		if v.OK() {
+			t.Fatal("Expected discrepancies")
+		}
+		if len(v.Missing) != 1 || v.Missing[0] != "file1.txt" {
+			t.Errorf("Unexpected missing: %v", v.Missing)
+		}
+		if len(v.Extra) != 1 || v.Extra[0] != "new.txt" {
+			t.Errorf("Unexpected extra: %v", v.Extra)
+		}
+		if len(v.Changed) != 1 || v.Changed[0] != "dir2/file5.txt" {
+			t.Errorf("Unexpected changed: %v", v.Changed)
+		}
+
+		var buf bytes.Buffer
+		if err := treego.PrintVerification(&buf, v); err != nil {
+			t.Fatalf("PrintVerification failed: %v", err)
+		}
+		want := "- file1.txt\n+ new.txt\n~ dir2/file5.txt\n1 missing, 1 extra, 1 changed\n"
+		if buf.String() != want {
+			t.Errorf("Expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("symlinks are not opened", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "sub", "real.txt"), []byte("real"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for link, target := range map[string]string{"dirlink": "sub", "dangling": "missing", "filelink": "sub/real.txt"} {
+			if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+				t.Skipf("symlinks unsupported: %v", err)
+			}
+		}
+
+		manifest := buildManifest(t, dir)
+		if len(manifest) != 1 || manifest["sub/real.txt"] == "" {
+			t.Errorf("Expected only the regular file, got %v", manifest)
+		}
+		// A sha256sum manifest may list a link; it is not checked.
+		manifest["filelink"] = manifest["sub/real.txt"]
+		resetGlobalState()
+		if v := treego.VerifyManifest(treego.BuildTreeSafe(dir), manifest); !v.OK() {
+			t.Errorf("Expected links to be skipped by verification, got %+v", v)
+		}
+
+		resetGlobalState()
+		root := treego.BuildFilteredTree(dir, treego.Options{FollowSymlinks: true})
+		var buf bytes.Buffer
+		if err := treego.WriteManifest(root, &buf); err != nil {
+			t.Fatalf("WriteManifest with followed links failed: %v", err)
+		}
+		for _, want := range []string{"  filelink\n", "  dirlink/real.txt\n", "  sub/real.txt\n"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("Expected %q in manifest:\n%s", want, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "dangling") {
+			t.Errorf("Expected the dangling link to be left out:\n%s", buf.String())
+		}
+	})
+
+	t.Run("verify unchanged tree is OK", func(t *testing.T) {
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		manifest := buildManifest(t, tmpDir)
+		resetGlobalState()
+		if v := treego.VerifyManifest(treego.BuildTreeSafe(tmpDir), manifest); !v.OK() {
+			t.Errorf("Expected unchanged tree to verify, got %+v", v)
+		}
+	})
+}
+
+func TestTreeHash(t *testing.T) {
+	hashOf := func(dir string) string {
+		t.Helper()
+		resetGlobalState()
+		sum, err := treego.TreeHash(treego.BuildFilteredTree(dir, treego.Options{}))
+		if err != nil {
+			t.Fatalf("TreeHash failed: %v", err)
+		}
+		return sum
+	}
+	a, cleanupA := createTestDir(t)
+	defer cleanupA()
+	b, cleanupB := createTestDir(t)
+	defer cleanupB()
+
+	base := hashOf(a)
+	if len(base) != 64 {
+		t.Fatalf("Expected a hex SHA-256 digest, got %q", base)
+	}
+	if got := hashOf(b); got != base {
+		t.Errorf("Expected identical trees to hash the same, got %s and %s", base, got)
+	}
+
+	// Child order must not matter.
+	root := treego.BuildFilteredTree(a, treego.Options{})
+	for i, j := 0, len(root.Children)-1; i < j; i, j = i+1, j-1 {
+		root.Children[i], root.Children[j] = root.Children[j], root.Children[i]
+	}
+	if got, _ := treego.TreeHash(root); got != base {
+		t.Errorf("Expected hash independent of child order")
+	}
+
+	if err := os.WriteFile(filepath.Join(b, "dir1", "file3.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hashOf(b) == base {
+		t.Error("Expected content change to change the hash")
+	}
+
+	if err := os.WriteFile(filepath.Join(b, "dir1", "file3.txt"), []byte("test content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(b, "dir2"), filepath.Join(b, "dir3")); err != nil {
+		t.Fatal(err)
+	}
+	if hashOf(b) == base {
+		t.Error("Expected rename to change the hash")
+	}
+
+	t.Run("symlinks hash by target", func(t *testing.T) {
+		withLinks := func(targets map[string]string) string {
+			t.Helper()
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for link, target := range targets {
+				if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+					t.Skipf("symlinks unsupported: %v", err)
+				}
+			}
+			return hashOf(dir)
+		}
+		links := map[string]string{"dirlink": "sub", "dangling": "missing"}
+		first := withLinks(links)
+		if again := withLinks(links); again != first {
+			t.Errorf("Expected the same links to hash the same, got %s and %s", first, again)
+		}
+		if other := withLinks(map[string]string{"dirlink": "sub", "dangling": "elsewhere"}); other == first {
+			t.Error("Expected a different link target to change the hash")
+		}
+	})
+}
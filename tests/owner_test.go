@@ -0,0 +1,120 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestShowOwner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("owners are not recorded on Windows")
+	}
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	if root := treego.BuildFilteredTree(tmpDir, treego.Options{}); root.Children[0].Owner != nil {
+		t.Error("Expected no owner without ShowOwner")
+	}
+
+	resetGlobalState()
+	root := treego.BuildFilteredTree(tmpDir, treego.Options{ShowOwner: true})
+	uid := uint32(os.Getuid())
+	var walk func(n *treego.Node)
+	walk = func(n *treego.Node) {
+		if n.Owner == nil || n.Owner.UID != uid {
+			t.Errorf("Expected owner uid %d on %s, got %+v", uid, n.Path, n.Owner)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	out := renderTreeOf(t, root, treego.Options{ShowOwner: true})
+	if want := "[" + treego.OwnerName(*root.Owner) + "]  file1.txt"; !strings.Contains(out, want) {
+		t.Errorf("Expected %q in output, got:\n%s", want, out)
+	}
+}
+
+func TestOwnerNameFallsBackToIDs(t *testing.T) {
+	if got := treego.OwnerName(treego.FileOwner{UID: 3999999999, GID: 3999999998}); got != "3999999999:3999999998" {
+		t.Errorf("Expected numeric ids for unknown owner, got %q", got)
+	}
+}
+
+func TestOwnedBy(t *testing.T) {
+	mine, theirs := &treego.FileOwner{UID: 1000}, &treego.FileOwner{UID: 0}
+	root := &treego.Node{Name: "home", IsDir: true, Owner: theirs, Children: []*treego.Node{
+		{Name: "me", IsDir: true, Owner: theirs, Children: []*treego.Node{
+			{Name: "notes.txt", Owner: mine},
+			{Name: "shared.txt", Owner: theirs},
+		}},
+		{Name: "other", IsDir: true, Owner: mine, Children: []*treego.Node{{Name: "x", Owner: theirs}}},
+		{Name: "unknown.txt"},
+	}}
+
+	got := treego.OwnedBy(root, 1000)
+	if got == nil || len(got.Children) != 1 || !equalNames(namesOf(got.Children[0].Children), []string{"notes.txt"}) {
+		t.Fatalf("Expected only me/notes.txt, got %+v", got)
+	}
+	if treego.OwnedBy(root, 42) != nil {
+		t.Error("Expected nil when no file matches")
+	}
+
+	if runtime.GOOS == "windows" {
+		if _, err := treego.CurrentUID(); err != treego.ErrNoOwners {
+			t.Errorf("Expected ErrNoOwners, got %v", err)
+		}
+		return
+	}
+	if uid, err := treego.CurrentUID(); err != nil || uid != uint32(os.Getuid()) {
+		t.Errorf("CurrentUID = %d, %v; want %d", uid, err, os.Getuid())
+	}
+}
+
+func TestFileTimes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("access and change times are not recorded on Windows")
+	}
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	atime := time.Date(2021, 3, 4, 5, 6, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(tmpDir, "file1.txt"), atime, atime); err != nil {
+		t.Fatal(err)
+	}
+	if root := treego.BuildFilteredTree(tmpDir, treego.Options{}); root.Children[0].AccessTime != nil {
+		t.Error("Expected no access time without ShowATime")
+	}
+
+	resetGlobalState()
+	opts := treego.Options{ShowATime: true, ShowCTime: true}
+	root := treego.BuildFilteredTree(tmpDir, opts)
+	var file *treego.Node
+	for _, c := range root.Children {
+		if c.Name == "file1.txt" {
+			file = c
+		}
+	}
+	if file.AccessTime == nil || !file.AccessTime.Equal(atime) {
+		t.Errorf("Expected access time %v, got %v", atime, file.AccessTime)
+	}
+	// Chtimes itself changes the inode, so ctime is recent.
+	if file.ChangeTime == nil || time.Since(*file.ChangeTime) > time.Hour {
+		t.Errorf("Expected a recent change time, got %v", file.ChangeTime)
+	}
+
+	opts.ShowCTime, opts.TimeFormat = false, "Jan _2 2006"
+	out := renderTreeOf(t, root, opts)
+	if !strings.Contains(out, "[Mar  4 2021]  file1.txt\n") {
+		t.Errorf("Expected formatted access time in output, got:\n%s", out)
+	}
+}
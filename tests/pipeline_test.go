@@ -0,0 +1,165 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+// unsortedTree is a hand-built tree that did not come from a scan.
+func unsortedTree() *treego.Node {
+	return &treego.Node{
+		Name:  "root",
+		Path:  "root",
+		IsDir: true,
+		Children: []*treego.Node{
+			{Name: "z.go", Path: "root/z.go"},
+			{Name: "b.txt", Path: "root/b.txt"},
+			{Name: "vendor", Path: "root/vendor", IsDir: true, Children: []*treego.Node{
+				{Name: "dep.go", Path: "root/vendor/dep.go"},
+			}},
+			{Name: "a.go", Path: "root/a.go"},
+			{Name: "cmd", Path: "root/cmd", IsDir: true},
+		},
+	}
+}
+
+func childNames(n *treego.Node) string {
+	var names []string
+	for _, c := range n.Children {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestPrepareTree(t *testing.T) {
+	t.Run("sorts without modifying the input", func(t *testing.T) {
+		in := unsortedTree()
+		out := treego.PrepareTree(in, treego.Options{})
+		if got := childNames(out); got != "cmd,vendor,a.go,b.txt,z.go" {
+			t.Errorf("Unexpected order: %s", got)
+		}
+		if got := childNames(in); got != "z.go,b.txt,vendor,a.go,cmd" {
+			t.Errorf("Expected input to be untouched, got %s", got)
+		}
+	})
+
+	t.Run("applies excludes and extensions", func(t *testing.T) {
+		excludes, _ := treego.ParseExcludeMatchers([]string{"vendor"})
+		out := treego.PrepareTree(unsortedTree(), treego.Options{Excludes: excludes, Extensions: []string{"go"}})
+		if got := childNames(out); got != "cmd,a.go,z.go" {
+			t.Errorf("Unexpected children: %s", got)
+		}
+	})
+
+	t.Run("excluded root yields nil", func(t *testing.T) {
+		excludes, _ := treego.ParseExcludeMatchers([]string{"root"})
+		if treego.PrepareTree(unsortedTree(), treego.Options{Excludes: excludes}) != nil {
+			t.Error("Expected nil for excluded root")
+		}
+	})
+}
+
+func TestRenderNode(t *testing.T) {
+	t.Run("renders a JSON-loaded tree like a scan", func(t *testing.T) {
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		resetGlobalState()
+		scanned := treego.BuildTreeSafe(tmpDir)
+		var js bytes.Buffer
+		if err := treego.TreeToJSON(scanned, &js); err != nil {
+			t.Fatalf("TreeToJSON failed: %v", err)
+		}
+		loaded, err := treego.TreeFromJSON(&js)
+		if err != nil {
+			t.Fatalf("TreeFromJSON failed: %v", err)
+		}
+
+		var fromScan, fromFile bytes.Buffer
+		opts := treego.Options{Extensions: []string{"txt"}}
+		if err := treego.RenderNode(scanned, &fromScan, "tree", opts); err != nil {
+			t.Fatalf("RenderNode failed: %v", err)
+		}
+		if err := treego.RenderNode(loaded, &fromFile, "tree", opts); err != nil {
+			t.Fatalf("RenderNode failed: %v", err)
+		}
+		if fromScan.String() != fromFile.String() {
+			t.Errorf("Expected identical output:\n%s\nvs\n%s", fromScan.String(), fromFile.String())
+		}
+		if strings.Contains(fromFile.String(), ".go") {
+			t.Errorf("Expected extension filter to apply, got %s", fromFile.String())
+		}
+	})
+
+	t.Run("dirs-only JSON has no file nodes", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.RenderNode(unsortedTree(), &buf, "json", treego.Options{DirsOnly: true}); err != nil {
+			t.Fatalf("RenderNode failed: %v", err)
+		}
+		root, err := treego.TreeFromJSON(&buf)
+		if err != nil {
+			t.Fatalf("TreeFromJSON failed: %v", err)
+		}
+		if got := childNames(root); got != "cmd,vendor" {
+			t.Errorf("Expected only directories, got %s", got)
+		}
+		if dirs, files := countNodes(root); dirs != 3 || files != 0 {
+			t.Errorf("Expected 3 directories and no files, got %d and %d", dirs, files)
+		}
+	})
+
+	t.Run("unknown format errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.RenderNode(unsortedTree(), &buf, "nope", treego.Options{}); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("file1.txt", filepath.Join(tmpDir, "link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resetGlobalState()
+	scanned := treego.BuildFilteredTree(tmpDir, treego.Options{ShowOwner: true, ShowATime: true, ShowCTime: true})
+	treego.AggregateAll(scanned)
+	var js bytes.Buffer
+	if err := treego.TreeToJSON(scanned, &js); err != nil {
+		t.Fatalf("TreeToJSON failed: %v", err)
+	}
+	loaded, err := treego.TreeFromJSON(&js)
+	if err != nil {
+		t.Fatalf("TreeFromJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, loaded) {
+		t.Errorf("Expected an identical tree after the round trip:\n%+v\nvs\n%+v", scanned, loaded)
+	}
+
+	var file, link *treego.Node
+	for _, c := range loaded.Children {
+		switch c.Name {
+		case "file1.txt":
+			file = c
+		case "link":
+			link = c
+		}
+	}
+	if file == nil || !file.Mode.IsRegular() || file.Mode.Perm() == 0 || !loaded.Mode.IsDir() {
+		t.Errorf("Expected modes to be recorded, got %+v", file)
+	}
+	if runtime.GOOS != "windows" && (link == nil || link.Mode&os.ModeSymlink == 0 || link.LinkTarget != "file1.txt") {
+		t.Errorf("Expected symlink details to be recorded, got %+v", link)
+	}
+}
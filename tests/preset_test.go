@@ -0,0 +1,84 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestExcludePreset(t *testing.T) {
+	common, ok := treego.ExcludePreset("common")
+	if !ok {
+		t.Fatal("Expected a common preset")
+	}
+	excludes, err := treego.ParseExcludeMatchers(common)
+	if err != nil {
+		t.Fatalf("Preset patterns do not parse: %v", err)
+	}
+
+	root := &treego.Node{Name: "app", Path: "app", IsDir: true, Children: []*treego.Node{
+		{Name: "node_modules", Path: "app/node_modules", IsDir: true, Children: []*treego.Node{{Name: "x.js", Path: "app/node_modules/x.js"}}},
+		{Name: "src", Path: "app/src", IsDir: true, Children: []*treego.Node{
+			{Name: "__pycache__", Path: "app/src/__pycache__", IsDir: true},
+			{Name: "main.py", Path: "app/src/main.py"},
+		}},
+		{Name: "yarn.lock", Path: "app/yarn.lock"},
+		{Name: ".DS_Store", Path: "app/.DS_Store"},
+		{Name: "README.md", Path: "app/README.md"},
+	}}
+	out := renderTreeOf(t, treego.PrepareTree(root, treego.Options{Excludes: excludes}), treego.Options{})
+	if want := "app\n├── src\n│   └── main.py\n└── README.md\n"; out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	// The returned slice is a copy.
+	common[0] = "changed"
+	if again, _ := treego.ExcludePreset("common"); again[0] == "changed" {
+		t.Error("Expected ExcludePreset to return a copy")
+	}
+	if _, ok := treego.ExcludePreset("nope"); ok {
+		t.Error("Expected unknown preset to be reported")
+	}
+}
+
+func TestApplyPresetOverrides(t *testing.T) {
+	got, err := treego.ApplyPresetOverrides([]string{"dist", "build", "*.lock"}, strings.NewReader("# local tweaks\n!build\n\nvendor\n"))
+	if err != nil {
+		t.Fatalf("ApplyPresetOverrides failed: %v", err)
+	}
+	if want := []string{"dist", "*.lock", "vendor"}; !equalNames(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadExcludePreset(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("relies on XDG_CONFIG_HOME")
+	}
+	cfg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", cfg)
+
+	base, _ := treego.ExcludePreset("common")
+	if got, err := treego.LoadExcludePreset("common"); err != nil || !equalNames(got, base) {
+		t.Errorf("Expected built-in list without a config file, got %v, %v", got, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(cfg, "treego"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg, "treego", "common-ignore"), []byte("!dist\ntmp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := treego.LoadExcludePreset("common")
+	if err != nil {
+		t.Fatalf("LoadExcludePreset failed: %v", err)
+	}
+	joined := strings.Join(got, " ")
+	if strings.Contains(" "+joined+" ", " dist ") || !strings.HasSuffix(joined, " tmp") {
+		t.Errorf("Expected dist removed and tmp added, got %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestFilePreviews(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":       "name: app\r\n\tport: 80\r\nmore: 1\n",
+		"conf/empty.ini": "",
+		"logo.png":       "\x89PNG\r\n\x1a\n\x00\x00",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := treego.FilePreview(filepath.Join(dir, "logo.png"), 5); got != nil {
+		t.Errorf("Expected no preview for a binary file, got %q", got)
+	}
+	if got := treego.FilePreview(filepath.Join(dir, "app.yaml"), 0); got != nil {
+		t.Errorf("Expected no preview for 0 lines, got %q", got)
+	}
+
+	resetGlobalState()
+	root := treego.BuildTreeSafe(dir)
+	treego.AttachPreviews(root, 2)
+	out := renderTreeOf(t, root, treego.Options{ShowPreview: true, RootLabel: "root"})
+	want := "root\n" +
+		"├── conf\n" +
+		"│   └── empty.ini\n" +
+		"├── app.yaml\n" +
+		"│   ┆ name: app\n" +
+		"│   ┆     port: 80\n" +
+		"└── logo.png\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	out = renderTreeOf(t, root, treego.Options{ShowPreview: true, ASCII: true, RootLabel: "root"})
+	if !strings.Contains(out, "|   > name: app\n") {
+		t.Errorf("Expected ASCII preview marker, got:\n%s", out)
+	}
+	if out := renderTreeOf(t, root, treego.Options{RootLabel: "root"}); strings.Contains(out, "name: app") {
+		t.Errorf("Expected previews hidden without ShowPreview, got:\n%s", out)
+	}
+}
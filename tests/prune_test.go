@@ -0,0 +1,196 @@
+package treego_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestPruneTree(t *testing.T) {
+	t.Run("keeps matches and their ancestors only", func(t *testing.T) {
+		in := sampleTree()
+		out := treego.PruneTree(in, func(n *treego.Node) bool { return n.Name == "main.go" })
+		if out == nil {
+			t.Fatal("Expected a pruned tree")
+		}
+		if len(out.Children) != 1 || out.Children[0].Name != "src" || out.Children[0].Children[0].Name != "main.go" {
+			t.Errorf("Unexpected pruned tree: %+v", out)
+		}
+		if len(in.Children) != 2 {
+			t.Error("Expected input tree to be untouched")
+		}
+	})
+
+	t.Run("nothing kept yields nil", func(t *testing.T) {
+		if treego.PruneTree(sampleTree(), func(*treego.Node) bool { return false }) != nil {
+			t.Error("Expected nil when nothing matches")
+		}
+	})
+}
+
+func TestPathTo(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	t.Run("glob pattern", func(t *testing.T) {
+		patterns, _ := treego.ParseExcludeMatchers([]string{"file4.*"})
+		out := renderTreeOf(t, treego.PathTo(root, patterns), treego.Options{RootLabel: "root"})
+		want := "root\n└── dir1\n    └── subdir1\n        └── file4.go\n"
+		if out != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+		}
+	})
+
+	t.Run("matched directory does not pull in its contents", func(t *testing.T) {
+		patterns, _ := treego.ParseExcludeMatchers([]string{"dir2", "re:^dep"})
+		out := renderTreeOf(t, treego.PathTo(root, patterns), treego.Options{RootLabel: "root"})
+		if strings.Contains(out, "file5.txt") || !strings.Contains(out, "dir2") || !strings.Contains(out, "dep.pem") {
+			t.Errorf("Unexpected output:\n%s", out)
+		}
+		if strings.Contains(out, "dir1") {
+			t.Errorf("Expected unrelated branches to be dropped:\n%s", out)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		patterns, _ := treego.ParseExcludeMatchers([]string{"missing.txt"})
+		if treego.PathTo(root, patterns) != nil {
+			t.Error("Expected nil for no matches")
+		}
+	})
+}
+
+func TestKeepPaths(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	list := "# changed files\ndir1/subdir1/file4.go\n\n" + filepath.Join(tmpDir, "file1.txt") + "\n  ./dir2 \nmissing.go\n"
+	paths, err := treego.ReadPathList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("Expected 4 paths, got %q", paths)
+	}
+
+	out, missing := treego.KeepPaths(root, paths)
+	got := renderTreeOf(t, out, treego.Options{RootLabel: "root"})
+	want := "root\n├── dir1\n│   └── subdir1\n│       └── file4.go\n├── dir2\n└── file1.txt\n"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+	if len(missing) != 1 || missing[0] != "missing.go" {
+		t.Errorf("Expected missing.go to be reported, got %q", missing)
+	}
+
+	if out, missing := treego.KeepPaths(root, []string{"nope"}); out != nil || len(missing) != 1 {
+		t.Errorf("Expected nil and one missing path, got %v, %q", out, missing)
+	}
+}
+
+func TestFoldIdentical(t *testing.T) {
+	module := func(name string, files ...string) *treego.Node {
+		src := &treego.Node{Name: "src", IsDir: true}
+		for _, f := range files {
+			src.Children = append(src.Children, &treego.Node{Name: f, Size: int64(len(name))})
+		}
+		return &treego.Node{Name: name, IsDir: true, Children: []*treego.Node{src, {Name: "go.mod"}}}
+	}
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		module("a", "main.go"),
+		module("b", "main.go"),
+		module("c", "main.go", "extra.go"),
+		{Name: "empty1", IsDir: true},
+		{Name: "empty2", IsDir: true},
+	}}
+
+	out := renderTreeOf(t, treego.FoldIdentical(root), treego.Options{})
+	want := "root\n" +
+		"├── a\n" +
+		"│   ├── src\n" +
+		"│   │   └── main.go\n" +
+		"│   └── go.mod\n" +
+		"├── b ≡ same as a\n" +
+		"├── c\n" +
+		"│   ├── src\n" +
+		"│   │   ├── main.go\n" +
+		"│   │   └── extra.go\n" +
+		"│   └── go.mod\n" +
+		"├── empty1\n" +
+		"└── empty2\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+	if len(root.Children[1].Children) != 2 || root.Children[1].SameAs != "" {
+		t.Error("Expected the input tree to be untouched")
+	}
+}
+
+func TestWithoutExtension(t *testing.T) {
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "docs", IsDir: true, Children: []*treego.Node{{Name: "README"}, {Name: "guide.md"}}},
+		{Name: "src", IsDir: true, Children: []*treego.Node{{Name: "main.go"}}},
+		{Name: ".gitignore"},
+		{Name: "configyml"},
+	}}
+	out := renderTreeOf(t, treego.WithoutExtension(root), treego.Options{})
+	if want := "root\n├── docs\n│   └── README\n└── configyml\n"; out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+	if treego.WithoutExtension(root.Children[1]) != nil {
+		t.Error("Expected nil when every file has an extension")
+	}
+}
+
+func TestModifiedSince(t *testing.T) {
+	now := time.Now()
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "old", IsDir: true, ModTime: now, Children: []*treego.Node{
+			{Name: "stale.txt", ModTime: now.Add(-48 * time.Hour)},
+		}},
+		{Name: "src", IsDir: true, Children: []*treego.Node{
+			{Name: "fresh.go", ModTime: now.Add(-time.Hour)},
+			{Name: "stale.go", ModTime: now.Add(-72 * time.Hour)},
+		}},
+	}}
+
+	out := renderTreeOf(t, treego.ModifiedSince(root, now.Add(-2*time.Hour)), treego.Options{})
+	if want := "root\n└── src\n    └── fresh.go\n"; out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+	if treego.ModifiedSince(root, now.Add(time.Minute)) != nil {
+		t.Error("Expected nil when nothing is recent enough")
+	}
+}
+
+func TestParseRecency(t *testing.T) {
+	valid := map[string]time.Duration{
+		"90m":   90 * time.Minute,
+		"2h30m": 150 * time.Minute,
+		"7d":    7 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+	}
+	for in, want := range valid {
+		if got, err := treego.ParseRecency(in); err != nil || got != want {
+			t.Errorf("ParseRecency(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "7", "d", "-2h", "0s", "1d12h", "3x"} {
+		if _, err := treego.ParseRecency(in); err == nil {
+			t.Errorf("ParseRecency(%q) succeeded; want an error", in)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestIsReadme(t *testing.T) {
+	for name, want := range map[string]bool{"README": true, "readme.md": true, "ReadMe.rst": true, "README.ja.md": true, "readmes": false, "notes.md": false} {
+		if got := treego.IsReadme(name); got != want {
+			t.Errorf("IsReadme(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReadmePreviews(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":      "# Project\n\n[![build](b.svg)](ci)\nTop level readme.\nSecond line.\nNot shown.\n",
+		"lib/Readme.rst": "Library\n=======\nShared helpers.\n",
+		"lib/util.go":    "package lib\n",
+		"bin/tool":       "",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resetGlobalState()
+	root := treego.BuildTreeSafe(dir)
+	treego.AttachReadmePreviews(root)
+
+	if got := strings.Join(root.Readme, "|"); got != "Project|Top level readme." {
+		t.Errorf("Unexpected root preview %q", got)
+	}
+	out := renderTreeOf(t, root, treego.Options{ShowReadme: true, RootLabel: "root"})
+	want := "root\n" +
+		"│   Project\n" +
+		"│   Top level readme.\n" +
+		"├── bin\n" +
+		"│   └── tool\n" +
+		"├── lib\n" +
+		"│   │   Library\n" +
+		"│   │   Shared helpers.\n" +
+		"│   ├── Readme.rst\n" +
+		"│   └── util.go\n" +
+		"└── README.md\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	t.Run("special files are not read", func(t *testing.T) {
+		dir := t.TempDir()
+		// Opening a FIFO would block until a writer shows up.
+		mkfifo(t, filepath.Join(dir, "README.md"))
+		resetGlobalState()
+		root := treego.BuildTreeSafe(dir)
+		withinTimeout(t, func() { treego.AttachReadmePreviews(root) })
+		if root.Readme != nil {
+			t.Errorf("Expected no preview, got %q", root.Readme)
+		}
+	})
+}
@@ -0,0 +1,399 @@
+package treego_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+// Helper function to build a small in-memory tree for renderer tests
+func sampleTree() *treego.Node {
+	return &treego.Node{
+		Name:  "root",
+		Path:  "root",
+		IsDir: true,
+		Children: []*treego.Node{
+			{
+				Name:  "src",
+				Path:  "root/src",
+				IsDir: true,
+				Children: []*treego.Node{
+					{Name: "main.go", Path: "root/src/main.go"},
+				},
+			},
+			{Name: "a&b <c>.txt", Path: "root/a&b <c>.txt"},
+		},
+	}
+}
+
+func renderString(t *testing.T, format string, opts treego.Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := treego.Render(sampleTree(), &buf, format, opts); err != nil {
+		t.Fatalf("Render(%s) failed: %v", format, err)
+	}
+	return buf.String()
+}
+
+func renderTreeOf(t *testing.T, root *treego.Node, opts treego.Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := treego.Render(root, &buf, "tree", opts); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRendererRegistry(t *testing.T) {
+	t.Run("built-in renderers are registered", func(t *testing.T) {
+		for _, name := range []string{"tree", "horizontal", "json", "tree-json", "ndjson", "yaml", "html", "dot", "plantuml", "csv", "markdown", "rst", "sql"} {
+			if _, ok := treego.LookupRenderer(name); !ok {
+				t.Errorf("Expected built-in renderer %q to be registered", name)
+			}
+		}
+		if _, ok := treego.LookupRenderer(treego.DefaultFormat); !ok {
+			t.Error("Expected default format to be registered")
+		}
+	})
+
+	t.Run("unknown format returns error", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.Render(sampleTree(), &buf, "nope", treego.Options{}); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+
+	t.Run("custom renderer can be registered", func(t *testing.T) {
+		treego.RegisterRenderer("count", treego.RendererFunc(func(node *treego.Node, w io.Writer, _ treego.Options) error {
+			_, err := io.WriteString(w, node.Name)
+			return err
+		}))
+
+		found := false
+		for _, name := range treego.RendererNames() {
+			if name == "count" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("Expected RendererNames to include custom renderer")
+		}
+
+		if out := renderString(t, "count", treego.Options{}); out != "root" {
+			t.Errorf("Expected custom renderer output 'root', got: %s", out)
+		}
+	})
+}
+
+func TestBuiltinRenderers(t *testing.T) {
+	t.Run("tree prints root header and honors dirs-only", func(t *testing.T) {
+		out := renderString(t, "tree", treego.Options{DirsOnly: true})
+		if !strings.HasPrefix(out, "root\n") {
+			t.Errorf("Expected root header line, got: %s", out)
+		}
+		if !strings.Contains(out, "src") || strings.Contains(out, "main.go") {
+			t.Errorf("Expected dirs-only tree output, got: %s", out)
+		}
+	})
+
+	t.Run("tree uses root label when set", func(t *testing.T) {
+		out := renderString(t, "tree", treego.Options{RootLabel: "/abs/root"})
+		if !strings.HasPrefix(out, "/abs/root\n") {
+			t.Errorf("Expected root label header, got: %s", out)
+		}
+	})
+
+	t.Run("json round-trips", func(t *testing.T) {
+		var decoded treego.Node
+		if err := json.Unmarshal([]byte(renderString(t, "json", treego.Options{})), &decoded); err != nil {
+			t.Fatalf("Invalid JSON output: %v", err)
+		}
+		if decoded.Name != "root" || len(decoded.Children) != 2 || decoded.Children[0].Children[0].Name != "main.go" {
+			t.Errorf("Unexpected decoded tree: %+v", decoded)
+		}
+	})
+
+	t.Run("yaml quotes names", func(t *testing.T) {
+		out := renderString(t, "yaml", treego.Options{})
+		if !strings.Contains(out, `name: "root"`) || !strings.Contains(out, `      - name: "main.go"`) {
+			t.Errorf("Unexpected YAML output: %s", out)
+		}
+	})
+
+	t.Run("html escapes names", func(t *testing.T) {
+		out := renderString(t, "html", treego.Options{})
+		if !strings.Contains(out, "a&amp;b &lt;c&gt;.txt") {
+			t.Errorf("Expected escaped file name in HTML output, got: %s", out)
+		}
+	})
+
+	t.Run("dot links parents to children", func(t *testing.T) {
+		out := renderString(t, "dot", treego.Options{})
+		if !strings.HasPrefix(out, "digraph treego {") || !strings.Contains(out, "n0 -> n1;") || !strings.Contains(out, "n1 -> n2;") {
+			t.Errorf("Unexpected DOT output: %s", out)
+		}
+	})
+
+	t.Run("tree-json matches tree -J", func(t *testing.T) {
+		out := renderString(t, "tree-json", treego.Options{})
+		want := "[\n" +
+			"  {\"type\":\"directory\",\"name\":\"root\",\"contents\":[\n" +
+			"    {\"type\":\"directory\",\"name\":\"src\",\"contents\":[\n" +
+			"      {\"type\":\"file\",\"name\":\"main.go\"}\n" +
+			"    ]},\n" +
+			"    {\"type\":\"file\",\"name\":\"a&b <c>.txt\"}\n" +
+			"  ]}\n" +
+			",\n" +
+			"  {\"type\":\"report\",\"directories\":1,\"files\":2}\n" +
+			"]\n"
+		if out != want {
+			t.Errorf("Expected %q, got %q", want, out)
+		}
+		var decoded []map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &decoded); err != nil || len(decoded) != 2 || decoded[1]["type"] != "report" {
+			t.Errorf("Expected valid JSON with a report, got %v (%v)", decoded, err)
+		}
+
+		var buf bytes.Buffer
+		link := &treego.Node{Name: "d", Path: "d", IsDir: true, Children: []*treego.Node{{Name: "l", LinkTarget: "../x"}, {Name: "e", IsDir: true}}}
+		if err := treego.TreeToTreeJSON(link, &buf); err != nil {
+			t.Fatalf("TreeToTreeJSON failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), `{"type":"link","name":"l","target":"../x"},`) || !strings.Contains(buf.String(), `{"type":"directory","name":"e","contents":[]}`) {
+			t.Errorf("Unexpected output for links and empty directories: %s", buf.String())
+		}
+	})
+
+	t.Run("horizontal centers directories against their children", func(t *testing.T) {
+		out := renderString(t, "horizontal", treego.Options{})
+		want := "root ─┬─ src ─── main.go\n" +
+			"      └─ a&b <c>.txt\n"
+		if out != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+		}
+
+		root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+			{Name: "one", IsDir: true, Children: []*treego.Node{{Name: "a"}, {Name: "b"}, {Name: "c"}}},
+			{Name: "z"},
+		}}
+		var buf bytes.Buffer
+		if err := treego.TreeToHorizontal(root, &buf, treego.Options{ASCII: true}); err != nil {
+			t.Fatal(err)
+		}
+		want = "              +- a\n" +
+			"      +- one -+- b\n" +
+			"root -+       `- c\n" +
+			"      `- z\n"
+		if buf.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("plantuml nests packages and escapes names", func(t *testing.T) {
+		out := renderString(t, "plantuml", treego.Options{})
+		want := "@startuml\n" +
+			"package \"root\" as n0 {\n" +
+			"  package \"src\" as n1 {\n" +
+			"    component \"main.go\" as n2\n" +
+			"  }\n" +
+			"  component \"a&b <U+003C>c>.txt\" as n3\n" +
+			"}\n" +
+			"@enduml\n"
+		if out != want {
+			t.Errorf("Expected %q, got %q", want, out)
+		}
+	})
+
+	t.Run("csv has one row per entry", func(t *testing.T) {
+		rows, err := csv.NewReader(strings.NewReader(renderString(t, "csv", treego.Options{}))).ReadAll()
+		if err != nil {
+			t.Fatalf("Invalid CSV output: %v", err)
+		}
+		if len(rows) != 5 {
+			t.Fatalf("Expected header + 4 rows, got %d", len(rows))
+		}
+		if rows[3][1] != "main.go" || rows[3][2] != "file" || rows[2][2] != "dir" {
+			t.Errorf("Unexpected CSV rows: %v", rows)
+		}
+	})
+
+	t.Run("markdown nests bullets", func(t *testing.T) {
+		out := renderString(t, "markdown", treego.Options{})
+		if !strings.Contains(out, "- root/\n  - src/\n    - main.go\n") {
+			t.Errorf("Unexpected Markdown output: %s", out)
+		}
+	})
+
+	t.Run("sql inserts rows with parent ids", func(t *testing.T) {
+		out := renderString(t, "sql", treego.Options{})
+		for _, want := range []string{
+			"CREATE TABLE files (",
+			"INSERT INTO files VALUES (1, NULL, 'root', 'root', 1, 0, NULL);",
+			"INSERT INTO files VALUES (2, 1, 'root/src', 'src', 1, 0, NULL);",
+			"INSERT INTO files VALUES (3, 2, 'root/src/main.go', 'main.go', 0, 0, NULL);",
+			"INSERT INTO files VALUES (4, 1, 'root/a&b <c>.txt', 'a&b <c>.txt', 0, 0, NULL);",
+			"COMMIT;\n",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected %q in SQL output:\n%s", want, out)
+			}
+		}
+
+		var buf bytes.Buffer
+		node := &treego.Node{Name: "it's", Path: "it's", ModTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+		if err := treego.TreeToSQL(node, &buf); err != nil {
+			t.Fatalf("TreeToSQL failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "(1, NULL, 'it''s', 'it''s', 0, 0, '2024-01-02T03:04:05Z');") {
+			t.Errorf("Expected quoted literals and timestamp, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("rst nests bullets with blank lines", func(t *testing.T) {
+		out := renderString(t, "rst", treego.Options{})
+		want := "- root/\n\n  - src/\n\n    - main.go\n\n  - a&b <c>.txt\n\n"
+		if out != want {
+			t.Errorf("Expected %q, got %q", want, out)
+		}
+
+		var buf bytes.Buffer
+		node := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{{Name: "__init__.py"}, {Name: "1.txt"}, {Name: "a*b|c"}}}
+		if err := treego.TreeToRST(node, &buf); err != nil {
+			t.Fatalf("TreeToRST failed: %v", err)
+		}
+		for _, item := range []string{`  - \_\_init\_\_.py`, `  - \1.txt`, `  - a\*b\|c`} {
+			if !strings.Contains(buf.String(), item+"\n") {
+				t.Errorf("Expected escaped item %q in %q", item, buf.String())
+			}
+		}
+	})
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	root := &treego.Node{Name: "repo", IsDir: true, Children: []*treego.Node{
+		{Name: "cmd", IsDir: true, Children: []*treego.Node{
+			{Name: "treego", IsDir: true, Children: []*treego.Node{{Name: "main.go"}}},
+		}},
+		{Name: "go.mod"},
+	}}
+
+	got := renderTreeOf(t, root, treego.Options{Breadcrumbs: 2})
+	want := "" +
+		"repo\n" +
+		"├── cmd\n" +
+		"│   ── cmd/treego ──\n" +
+		"│   └── treego\n" +
+		"│       └── main.go\n" +
+		"└── go.mod\n"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := renderTreeOf(t, root, treego.Options{Breadcrumbs: 1}); !strings.HasPrefix(got, "repo\n── cmd ──\n├── cmd\n") {
+		t.Errorf("Expected a top-level breadcrumb, got:\n%s", got)
+	}
+}
+
+func TestASCIIAndLimits(t *testing.T) {
+	root := &treego.Node{Name: "repo", IsDir: true, Children: []*treego.Node{
+		{Name: "cmd", IsDir: true, Children: []*treego.Node{
+			{Name: "treego", IsDir: true, Children: []*treego.Node{{Name: "main.go"}}},
+		}},
+		{Name: "a-file-with-a-rather-long-name.txt"},
+	}}
+
+	got := renderTreeOf(t, root, treego.Options{ASCII: true, MaxDepth: 2, Breadcrumbs: 1})
+	want := "" +
+		"repo\n" +
+		"-- cmd --\n" +
+		"|-- cmd\n" +
+		"|   `-- treego\n" +
+		"`-- a-file-with-a-rather-long-name.txt\n"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	got = renderTreeOf(t, root, treego.Options{MaxDepth: 1, MaxWidth: 20})
+	want = "" +
+		"repo\n" +
+		"├── cmd\n" +
+		"└── a-file-with-a...\n"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestCollapseExt(t *testing.T) {
+	root := &treego.Node{Name: "assets", IsDir: true}
+	root.Children = append(root.Children, &treego.Node{Name: "icons", IsDir: true, Children: []*treego.Node{{Name: "a.svg"}}})
+	for _, name := range []string{"Makefile", "a.jpg", "a.png", "b.JPG", "c.jpg", "d.jpg"} {
+		root.Children = append(root.Children, &treego.Node{Name: name})
+	}
+
+	got := renderTreeOf(t, root, treego.Options{CollapseExt: 2})
+	want := "" +
+		"assets\n" +
+		"├── icons\n" +
+		"│   └── a.svg\n" +
+		"├── Makefile\n" +
+		"├── a.jpg\n" +
+		"├── a.png\n" +
+		"├── b.JPG\n" +
+		"└── ... and 2 more .jpg\n"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if full := renderTreeOf(t, root, treego.Options{}); strings.Contains(full, "more") {
+		t.Errorf("Expected no collapsing by default, got:\n%s", full)
+	}
+}
+
+func TestTreeToSitemap(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	root := &treego.Node{Name: "public", IsDir: true, Children: []*treego.Node{
+		{Name: "docs", IsDir: true, Children: []*treego.Node{
+			{Name: "index.html", ModTime: mtime},
+			{Name: "a b&c.htm"},
+		}},
+		{Name: "style.css"},
+		{Name: "report.pdf"},
+		{Name: "index.html"},
+	}}
+
+	var buf bytes.Buffer
+	if err := treego.TreeToSitemap(root, "https://example.com/", &buf); err != nil {
+		t.Fatalf("TreeToSitemap failed: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/docs/</loc>
+    <lastmod>2024-01-02T03:04:05Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/docs/a%20b&amp;c.htm</loc>
+  </url>
+  <url>
+    <loc>https://example.com/</loc>
+  </url>
+</urlset>
+`
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := treego.TreeToSitemapExt(root, "https://example.com/site", []string{".PDF"}, &buf); err != nil {
+		t.Fatalf("TreeToSitemapExt failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "<loc>https://example.com/site/report.pdf</loc>") || strings.Contains(out, ".htm") {
+		t.Errorf("Expected only the PDF, got:\n%s", out)
+	}
+}
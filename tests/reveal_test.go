@@ -0,0 +1,68 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestRevealFrames(t *testing.T) {
+	frames, err := treego.RevealFrames(sampleTree(), treego.Options{})
+	if err != nil {
+		t.Fatalf("RevealFrames failed: %v", err)
+	}
+	want := []string{
+		"root\n├── src\n└── a&b <c>.txt\n",
+		"root\n├── src\n│   └── main.go\n└── a&b <c>.txt\n",
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("Expected %d frames, got %q", len(want), frames)
+	}
+	for i := range want {
+		if frames[i] != want[i] {
+			t.Errorf("frame %d = %q; want %q", i, frames[i], want[i])
+		}
+	}
+
+	if frames, _ := treego.RevealFrames(sampleTree(), treego.Options{MaxDepth: 1}); len(frames) != 1 {
+		t.Errorf("Expected MaxDepth to cap the frames, got %d", len(frames))
+	}
+}
+
+func TestRevealWithoutTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	if err := treego.Reveal(&buf, sampleTree(), treego.Options{}, 0); err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	if want := renderString(t, "tree", treego.Options{}); buf.String() != want {
+		t.Errorf("Expected the plain tree, got %q", buf.String())
+	}
+}
+
+func TestRevealColorFollowsWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := treego.Reveal(&buf, sampleTree(), treego.Options{Color: treego.ColorForce}, 0); err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	if want := renderString(t, "tree", treego.Options{Color: treego.ColorForce}); buf.String() != want || !strings.Contains(want, "\x1b[") {
+		t.Errorf("Expected the colored tree, got %q", buf.String())
+	}
+
+	// ColorAlways is plain in a regular file, though the frames are first
+	// drawn into buffers.
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := treego.Reveal(f, sampleTree(), treego.Options{Color: treego.ColorAlways}, 0); err != nil {
+		t.Fatalf("Reveal failed: %v", err)
+	}
+	if data, _ := os.ReadFile(f.Name()); strings.Contains(string(data), "\x1b") {
+		t.Errorf("Expected no escape codes in a file, got %q", data)
+	}
+}
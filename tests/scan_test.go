@@ -0,0 +1,281 @@
+package treego_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestScan(t *testing.T) {
+	t.Run("bundles tree and counts", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		res, err := treego.Scan(tmpDir, treego.Options{})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if res.Root == nil || res.Partial || len(res.Errors) != 0 {
+			t.Fatalf("Expected complete scan, got %+v", res)
+		}
+		// Six fixture files of 12 bytes each.
+		if res.Dirs != 5 || res.Files != 6 || res.Bytes != 72 {
+			t.Errorf("Expected 5 dirs, 6 files, 72 bytes; got %d, %d, %d", res.Dirs, res.Files, res.Bytes)
+		}
+		if res.Duration <= 0 {
+			t.Error("Expected a positive duration")
+		}
+	})
+
+	t.Run("summary only drops file nodes but keeps totals", func(t *testing.T) {
+		resetGlobalState()
+		tmpDir, cleanup := createTestDir(t)
+		defer cleanup()
+
+		res, err := treego.Scan(tmpDir, treego.Options{SummaryOnly: true})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if dirs, files := countNodes(res.Root); dirs != 5 || files != 0 {
+			t.Errorf("Expected 5 directory nodes and no file nodes, got %d and %d", dirs, files)
+		}
+		if res.Dirs != 5 || res.Files != 6 || res.Bytes != 72 {
+			t.Errorf("Expected 5 dirs, 6 files, 72 bytes; got %d, %d, %d", res.Dirs, res.Files, res.Bytes)
+		}
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		resetGlobalState()
+		defer resetGlobalState()
+
+		missing := filepath.Join(t.TempDir(), "missing")
+		res, err := treego.Scan(missing, treego.Options{})
+		if !os.IsNotExist(err) {
+			t.Fatalf("Expected not-exist error, got %v", err)
+		}
+		if res == nil || res.Root != nil || len(res.Errors) != 1 {
+			t.Errorf("Expected nil root and one error, got %+v", res)
+		}
+	})
+
+	t.Run("keeps partial results", func(t *testing.T) {
+		resetGlobalState()
+		defer resetGlobalState()
+
+		dir := t.TempDir()
+		tooLongDir(t, dir, "deep")
+		if err := os.WriteFile(filepath.Join(dir, "top.txt"), []byte("hello"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		res, err := treego.Scan(dir, treego.Options{})
+		if err == nil || len(res.Errors) != 1 || err != res.Errors[0] {
+			t.Fatalf("Expected the one error to be returned, got %v and %v", err, res.Errors)
+		}
+		if res.Root == nil {
+			t.Fatal("Expected the readable part of the tree")
+		}
+		dirs, files := countNodes(res.Root)
+		// The root and every directory of the chain but the unreadable one.
+		if res.Dirs != dirs || res.Files != 1 || files != 1 || res.Bytes != 5 {
+			t.Errorf("Expected totals of the built tree (%d dirs, 1 file, 5 bytes), got %d, %d, %d", dirs, res.Dirs, res.Files, res.Bytes)
+		}
+	})
+}
+
+func TestWriteErrorsJSON(t *testing.T) {
+	errs := []error{
+		&fs.PathError{Op: "open", Path: "/srv/private", Err: fs.ErrPermission},
+		errors.New("something else"),
+	}
+	var buf bytes.Buffer
+	if err := treego.WriteErrorsJSON(&buf, errs); err != nil {
+		t.Fatalf("WriteErrorsJSON failed: %v", err)
+	}
+	want := `{"path":"/srv/private","op":"open","message":"permission denied"}` + "\n" +
+		`{"message":"something else"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestRenderProgressive(t *testing.T) {
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	for _, opts := range []treego.Options{
+		{},
+		{Sort: treego.SortMtime, CollapseExt: 1},
+		{DirsOnly: true, ASCII: true, MaxDepth: 2},
+		{Matcher: regexp.MustCompile(`\.go$`), Extensions: []string{"go"}},
+		{Sort: treego.SortHot, ShowDirSize: true},
+	} {
+		resetGlobalState()
+		want := renderTreeOf(t, treego.BuildFilteredTree(tmpDir, opts), opts)
+		resetGlobalState()
+		var buf bytes.Buffer
+		res, err := treego.RenderProgressive(context.Background(), &buf, tmpDir, opts)
+		if err != nil || res.Root == nil {
+			t.Fatalf("RenderProgressive with %+v: %v, %+v", opts, err, res)
+		}
+		if buf.String() != want {
+			t.Errorf("With %+v expected:\n%s\ngot:\n%s", opts, want, buf.String())
+		}
+	}
+
+	t.Run("prints before the scan finishes", func(t *testing.T) {
+		// The root directory is reported to OnDir only once every entry
+		// is built, so output written before that was streamed.
+		var rootDone, streamed atomic.Bool
+		opts := treego.Options{OnDir: func(n *treego.Node) {
+			if n.Path == tmpDir {
+				rootDone.Store(true)
+			}
+		}}
+		w := writerFunc(func(p []byte) (int, error) {
+			if !rootDone.Load() {
+				streamed.Store(true)
+			}
+			return len(p), nil
+		})
+		resetGlobalState()
+		if _, err := treego.RenderProgressive(context.Background(), w, tmpDir, opts); err != nil {
+			t.Fatal(err)
+		}
+		if !streamed.Load() {
+			t.Error("Expected output before the root finished building")
+		}
+	})
+
+	t.Run("file root", func(t *testing.T) {
+		resetGlobalState()
+		var buf bytes.Buffer
+		if _, err := treego.RenderProgressive(context.Background(), &buf, filepath.Join(tmpDir, "file1.txt"), treego.Options{}); err != nil || buf.String() != "file1.txt\n" {
+			t.Errorf("Expected the file name, got %q, %v", buf.String(), err)
+		}
+	})
+
+	t.Run("last entry fails to build", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("relies on the Unix PATH_MAX limit")
+		}
+		// Nest the root so deep that its listing can be read but the last
+		// entry, whose name is long, cannot be opened.
+		root := t.TempDir()
+		for len(root) < 3900 {
+			root = filepath.Join(root, strings.Repeat("d", 200))
+		}
+		if err := os.MkdirAll(filepath.Join(root, "a"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(root); err != nil {
+			t.Fatal(err)
+		}
+		err = os.Mkdir(strings.Repeat("z", 250), 0o755)
+		os.Chdir(cwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opts := treego.Options{RootLabel: "root"}
+		resetGlobalState()
+		res, _ := treego.Scan(root, opts)
+		if len(res.Errors) != 1 {
+			t.Fatalf("Expected the long entry to fail, got %v", res.Errors)
+		}
+		want := renderTreeOf(t, res.Root, opts)
+		resetGlobalState()
+		var buf bytes.Buffer
+		if _, err := treego.RenderProgressive(context.Background(), &buf, root, opts); err != nil {
+			t.Fatalf("RenderProgressive failed: %v", err)
+		}
+		if want != "root\n└── a\n" || buf.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+		}
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// tooLongDir creates a chain of directories starting at dir/name whose
+// deepest path is longer than PATH_MAX, so it cannot be read even as root.
+func tooLongDir(t *testing.T, dir, name string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on the Unix PATH_MAX limit")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// Relative steps keep each call short while the full path grows.
+	for part, path := name, dir; len(path) <= 4096; part = strings.Repeat("d", 200) {
+		if err := os.Mkdir(part, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(part); err != nil {
+			t.Fatal(err)
+		}
+		path += "/" + part
+	}
+}
+
+func TestScanContinuesOnError(t *testing.T) {
+	dir := t.TempDir()
+	tooLongDir(t, dir, "a")
+	tooLongDir(t, dir, "b")
+	for _, f := range []string{"ok/file.txt", "top.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resetGlobalState()
+	defer resetGlobalState()
+	res, _ := treego.Scan(dir, treego.Options{})
+	if len(res.Errors) != 2 {
+		t.Fatalf("Expected one error per unreadable chain, got %v", res.Errors)
+	}
+	for _, err := range res.Errors {
+		var pe *fs.PathError
+		if !errors.As(err, &pe) {
+			t.Errorf("Expected a path error, got %T: %v", err, err)
+		}
+	}
+	out := renderTreeOf(t, res.Root, treego.Options{MaxDepth: 1, RootLabel: "root"})
+	want := "root\n" +
+		"├── a\n" +
+		"├── b\n" +
+		"├── ok\n" +
+		"└── top.txt\n"
+	if out != want {
+		t.Errorf("Expected the readable entries after the errors:\n%s\ngot:\n%s", want, out)
+	}
+	if !strings.Contains(renderTreeOf(t, res.Root, treego.Options{}), "file.txt") {
+		t.Error("Expected ok/file.txt in the tree")
+	}
+}
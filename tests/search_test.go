@@ -0,0 +1,127 @@
+package treego_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestSearchCounts(t *testing.T) {
+	resetGlobalState()
+	tmpDir, cleanup := createTestDir(t)
+	defer cleanup()
+
+	root := treego.BuildTreeSafe(tmpDir)
+	if root == nil {
+		t.Fatal("Failed to build tree")
+	}
+
+	t.Run("counts matching descendants per directory", func(t *testing.T) {
+		counts := treego.SearchCounts(root, ".TXT")
+		got := map[string]int{}
+		for _, c := range counts {
+			got[c.Node.Name] = c.Count
+		}
+
+		want := map[string]int{filepath.Base(tmpDir): 3, "dir1": 1, "dir2": 1}
+		if len(got) != len(want) {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+		for name, n := range want {
+			if got[name] != n {
+				t.Errorf("Expected %s to have %d matches, got %d", name, n, got[name])
+			}
+		}
+		if counts[0].Node != root {
+			t.Error("Expected root to be listed first")
+		}
+	})
+
+	t.Run("matching directories count toward their parent", func(t *testing.T) {
+		counts := treego.SearchCounts(root, "subdir")
+		if len(counts) != 2 || counts[0].Count != 1 || counts[1].Node.Name != "dir1" {
+			t.Errorf("Expected root and dir1 with one match each, got %+v", counts)
+		}
+	})
+
+	t.Run("no matches prints nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintSearchCounts(&buf, treego.SearchCounts(root, "nonexistent")); err != nil {
+			t.Fatalf("PrintSearchCounts failed: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected empty output, got: %s", buf.String())
+		}
+	})
+
+	t.Run("print format", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintSearchCounts(&buf, treego.SearchCounts(root, "file4")); err != nil {
+			t.Fatalf("PrintSearchCounts failed: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 || !strings.HasSuffix(lines[2], filepath.Join("dir1", "subdir1")) || !strings.HasPrefix(strings.TrimSpace(lines[2]), "1  ") {
+			t.Errorf("Unexpected output: %q", buf.String())
+		}
+	})
+}
+
+func TestPrintSearch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := treego.PrintSearch(&buf, sampleTree(), "MAIN"); err != nil {
+		t.Fatalf("PrintSearch failed: %v", err)
+	}
+	if buf.String() != "root/src/main.go\n" {
+		t.Errorf("Expected single match, got %q", buf.String())
+	}
+}
+
+func TestPrintLevel(t *testing.T) {
+	root := &treego.Node{Name: "repo", IsDir: true, Children: []*treego.Node{
+		{Name: "services", IsDir: true, Children: []*treego.Node{
+			{Name: "api", IsDir: true, Children: []*treego.Node{{Name: "main.go"}}},
+			{Name: "web", IsDir: true},
+			{Name: "README.md"},
+		}},
+		{Name: "go.mod"},
+	}}
+	cases := []struct {
+		depth    int
+		dirsOnly bool
+		want     string
+	}{
+		{1, false, "services/\ngo.mod\n"},
+		{2, false, "services/api/\nservices/web/\nservices/README.md\n"},
+		{2, true, "services/api/\nservices/web/\n"},
+		{3, false, "services/api/main.go\n"},
+		{4, false, ""},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := treego.PrintLevel(&buf, root, c.depth, treego.Options{DirsOnly: c.dirsOnly}); err != nil {
+			t.Fatalf("PrintLevel failed: %v", err)
+		}
+		if buf.String() != c.want {
+			t.Errorf("PrintLevel(%d, dirsOnly=%v) = %q; want %q", c.depth, c.dirsOnly, buf.String(), c.want)
+		}
+	}
+}
+
+func TestRebasePaths(t *testing.T) {
+	base := t.TempDir()
+	root := &treego.Node{Name: "proj", IsDir: true, Path: filepath.Join(base, "proj"), Children: []*treego.Node{
+		{Name: "main.go", Path: filepath.Join(base, "proj", "main.go")},
+	}}
+	if err := treego.RebasePaths(root, filepath.Join(base, "other")); err != nil {
+		t.Fatalf("RebasePaths failed: %v", err)
+	}
+	if want := filepath.Join("..", "proj"); root.Path != want {
+		t.Errorf("Expected root path %q, got %q", want, root.Path)
+	}
+	if want := filepath.Join("..", "proj", "main.go"); root.Children[0].Path != want {
+		t.Errorf("Expected child path %q, got %q", want, root.Children[0].Path)
+	}
+}
@@ -0,0 +1,73 @@
+package treego_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestHumanizeSize(t *testing.T) {
+	cases := []struct {
+		n    int64
+		base int64
+		want string
+	}{
+		{0, treego.BinaryBase, "0 B"},
+		{1023, treego.BinaryBase, "1023 B"},
+		{1024, treego.BinaryBase, "1.0 KiB"},
+		{1536, treego.BinaryBase, "1.5 KiB"},
+		{5 * 1024 * 1024, treego.BinaryBase, "5.0 MiB"},
+		{999, treego.SIBase, "999 B"},
+		{1000, treego.SIBase, "1.0 KB"},
+		{1200000, treego.SIBase, "1.2 MB"},
+		{1 << 62, treego.BinaryBase, "4.0 EiB"},
+		{2048, 0, "2.0 KiB"},
+	}
+	for _, c := range cases {
+		if got := treego.HumanizeSize(c.n, c.base); got != c.want {
+			t.Errorf("HumanizeSize(%d, %d) = %q; want %q", c.n, c.base, got, c.want)
+		}
+	}
+}
+
+func TestTreeShowsSizes(t *testing.T) {
+	root := sampleTree()
+	root.Children[0].Children[0].Size = 1500
+
+	binary := renderTreeOf(t, root, treego.Options{ShowSize: true})
+	if !strings.Contains(binary, "[1.5 KiB]  main.go") {
+		t.Errorf("Expected binary size, got:\n%s", binary)
+	}
+	if strings.Contains(binary, "]  src") {
+		t.Errorf("Expected no size for directories, got:\n%s", binary)
+	}
+
+	si := renderTreeOf(t, root, treego.Options{ShowSize: true, SI: true})
+	if !strings.Contains(si, "[1.5 KB]  main.go") {
+		t.Errorf("Expected SI size, got:\n%s", si)
+	}
+}
+
+func TestRunningTotal(t *testing.T) {
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "src", IsDir: true, Children: []*treego.Node{{Name: "big.bin", Size: 3000}}},
+		{Name: "a.txt", Size: 1000},
+		{Name: "b.txt", Size: 24},
+	}}
+	out := renderTreeOf(t, root, treego.Options{RunningTotal: true, SI: true})
+	want := "root\n" +
+		"├── src\n" +
+		"│   └── [3.0 KB, total 3.0 KB]  big.bin\n" +
+		"├── [1.0 KB, total 4.0 KB]  a.txt\n" +
+		"└── [24 B, total 4.0 KB]  b.txt\n"
+	if out != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out)
+	}
+
+	// Files that are not printed are not counted.
+	out = renderTreeOf(t, root, treego.Options{RunningTotal: true, SI: true, MaxDepth: 1})
+	if !strings.Contains(out, "[1.0 KB, total 1.0 KB]  a.txt") {
+		t.Errorf("Expected hidden files to be left out of the total, got:\n%s", out)
+	}
+}
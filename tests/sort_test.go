@@ -0,0 +1,261 @@
+package treego_test
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func namesOf(nodes []*treego.Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.Name)
+	}
+	return out
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func nodesNamed(names ...string) []*treego.Node {
+	var out []*treego.Node
+	for _, n := range names {
+		out = append(out, &treego.Node{Name: n})
+	}
+	return out
+}
+
+func TestSortNodes(t *testing.T) {
+	t.Run("case-insensitive by default with stable tiebreak", func(t *testing.T) {
+		nodes := nodesNamed("readme", "Zeta", "alpha", "Readme", "beta")
+		treego.SortNodes(nodes, treego.Options{})
+		want := []string{"alpha", "beta", "Readme", "readme", "Zeta"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+
+		// The tiebreak makes the result independent of input order.
+		reversed := nodesNamed("beta", "Readme", "alpha", "Zeta", "readme")
+		treego.SortNodes(reversed, treego.Options{})
+		if got := namesOf(reversed); !equalNames(got, want) {
+			t.Errorf("Expected %v regardless of input order, got %v", want, got)
+		}
+	})
+
+	t.Run("case-sensitive byte order", func(t *testing.T) {
+		nodes := nodesNamed("readme", "Zeta", "alpha", "Readme")
+		treego.SortNodes(nodes, treego.Options{SortCaseSensitive: true})
+		want := []string{"Readme", "Zeta", "alpha", "readme"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("directories first", func(t *testing.T) {
+		nodes := nodesNamed("a.txt", "B")
+		nodes[1].IsDir = true
+		treego.SortNodes(nodes, treego.Options{})
+		if nodes[0].Name != "B" {
+			t.Errorf("Expected directory first, got %v", namesOf(nodes))
+		}
+	})
+}
+
+func TestSortModes(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		for in, want := range map[string]treego.SortMode{"": treego.SortName, "name": treego.SortName, "natural": treego.SortNatural, "size": treego.SortSize, "mtime": treego.SortMtime, "ext": treego.SortExt, "hot": treego.SortHot} {
+			if got, err := treego.ParseSortMode(in); err != nil || got != want {
+				t.Errorf("ParseSortMode(%q) = %v, %v; want %v", in, got, err, want)
+			}
+		}
+		if _, err := treego.ParseSortMode("random"); err == nil {
+			t.Error("Expected error for invalid sort mode")
+		}
+	})
+
+	t.Run("natural", func(t *testing.T) {
+		nodes := nodesNamed("file10.log", "file2.log", "File1.log", "file02.log", "file", "img007b", "img7a")
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortNatural})
+		want := []string{"file", "File1.log", "file02.log", "file2.log", "file10.log", "img7a", "img007b"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("natural less handles long numbers", func(t *testing.T) {
+		if !treego.NaturalLess("v99999999999999999999", "v100000000000000000000") {
+			t.Error("Expected numeric comparison of long digit runs")
+		}
+		if treego.NaturalLess("a10", "a9") {
+			t.Error("Expected a9 before a10")
+		}
+	})
+
+	t.Run("size and mtime", func(t *testing.T) {
+		now := time.Now()
+		nodes := nodesNamed("small", "big", "mid")
+		nodes[0].Size, nodes[1].Size, nodes[2].Size = 1, 100, 10
+		nodes[0].ModTime, nodes[1].ModTime, nodes[2].ModTime = now, now.Add(-time.Hour), now.Add(time.Hour)
+
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortSize})
+		if got := namesOf(nodes); !equalNames(got, []string{"big", "mid", "small"}) {
+			t.Errorf("Expected largest first, got %v", got)
+		}
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortMtime})
+		if got := namesOf(nodes); !equalNames(got, []string{"mid", "small", "big"}) {
+			t.Errorf("Expected newest first, got %v", got)
+		}
+	})
+
+	t.Run("hot", func(t *testing.T) {
+		now := time.Now()
+		old := now.Add(-24 * time.Hour)
+		dir := func(name string, mod time.Time, children ...*treego.Node) *treego.Node {
+			return &treego.Node{Name: name, IsDir: true, ModTime: mod, Children: children}
+		}
+		nodes := []*treego.Node{
+			dir("docs", old, &treego.Node{Name: "a.md", ModTime: old}),
+			dir("src", old, dir("deep", old, &treego.Node{Name: "new.go", ModTime: now})),
+			{Name: "old.txt", ModTime: old},
+			{Name: "recent.txt", ModTime: now.Add(-time.Hour)},
+		}
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortHot})
+		want := []string{"src", "docs", "recent.txt", "old.txt"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+		if got := treego.LatestModTime(nodes[0]); !got.Equal(now) {
+			t.Errorf("LatestModTime = %v; want %v", got, now)
+		}
+	})
+
+	t.Run("ext", func(t *testing.T) {
+		nodes := nodesNamed("b.md", "z.go", "Makefile", "a.GO", "c.md", "lib")
+		nodes[5].IsDir = true
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortExt})
+		want := []string{"lib", "Makefile", "a.GO", "z.go", "b.md", "c.md"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+}
+
+// writeShuffledTree creates the given files (and their directories) under
+// dir in a random order, then pins every mtime so only the order the
+// filesystem returns entries in can differ between copies.
+func writeShuffledTree(t *testing.T, dir string, files []string, rng *rand.Rand) {
+	t.Helper()
+	order := append([]string(nil), files...)
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	for _, f := range order {
+		p := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := filepath.Walk(dir, func(p string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(p, stamp, stamp)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeterministicOrder(t *testing.T) {
+	t.Run("overrides sort mode", func(t *testing.T) {
+		nodes := nodesNamed("b", "B", "a", "_x", "A")
+		nodes = append(nodes, &treego.Node{Name: "z", IsDir: true})
+		nodes[0].Size = 100
+		treego.SortNodes(nodes, treego.Options{Sort: treego.SortSize, Deterministic: true})
+		want := []string{"z", "A", "B", "_x", "a", "b"}
+		if got := namesOf(nodes); !equalNames(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("identical output across runs and shuffled filesystems", func(t *testing.T) {
+		files := []string{
+			"README.md", "readme.txt", "Makefile", "a/b/c.go", "a/B.go", "a/a.go",
+			"z/10.txt", "z/2.txt", "z/_.txt", "Docs/index.md", "docs/x.md", "file2", "file10",
+		}
+		opts := treego.Options{Deterministic: true}
+		render := func(dir string) string {
+			resetGlobalState()
+			root := treego.BuildFilteredTree(dir, opts)
+			if err := treego.RebasePaths(root, dir); err != nil {
+				t.Fatal(err)
+			}
+			root.Name = "root"
+			var buf bytes.Buffer
+			if err := treego.RenderNode(root, &buf, "json", opts); err != nil {
+				t.Fatal(err)
+			}
+			return buf.String()
+		}
+
+		var want string
+		for seed := int64(1); seed <= 5; seed++ {
+			dir := t.TempDir()
+			writeShuffledTree(t, dir, files, rand.New(rand.NewSource(seed)))
+			for run := 0; run < 3; run++ {
+				got := render(dir)
+				if want == "" {
+					want = got
+				} else if got != want {
+					t.Fatalf("seed %d run %d: output differs:\n%s\nwant:\n%s", seed, run, got, want)
+				}
+			}
+		}
+	})
+}
+
+func TestRankRecent(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	file := func(name string, age time.Duration) *treego.Node {
+		return &treego.Node{Name: name, ModTime: base.Add(-age)}
+	}
+	old, newest, tieA, tieB := file("old.txt", 4*time.Hour), file("new.txt", 0), file("a.go", time.Hour), file("b.go", time.Hour)
+	sub := &treego.Node{Name: "sub", IsDir: true, ModTime: base.Add(time.Hour), Children: []*treego.Node{tieA, tieB}}
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{sub, old, newest}}
+
+	treego.RankRecent(root, 0)
+	for _, tc := range []struct {
+		n    *treego.Node
+		want int
+	}{{newest, 1}, {tieA, 2}, {tieB, 2}, {old, 4}, {sub, 0}} {
+		if tc.n.Rank != tc.want {
+			t.Errorf("%s: rank %d, want %d", tc.n.Name, tc.n.Rank, tc.want)
+		}
+	}
+
+	treego.RankRecent(root, 2)
+	if old.Rank != 0 || tieA.Rank != 2 {
+		t.Errorf("With top 2: old.txt rank %d, a.go rank %d; want 0 and 2", old.Rank, tieA.Rank)
+	}
+	out := renderTreeOf(t, root, treego.Options{ShowRank: true, Color: treego.ColorNever})
+	if !strings.Contains(out, "new.txt #1") || strings.Contains(out, "old.txt #") {
+		t.Errorf("Expected only the top ranks printed, got:\n%s", out)
+	}
+}
@@ -0,0 +1,199 @@
+package treego_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func statsTree() *treego.Node {
+	return &treego.Node{
+		Name:  "root",
+		IsDir: true,
+		Children: []*treego.Node{
+			{Name: "src", IsDir: true, Children: []*treego.Node{
+				{Name: "main.go", Size: 500},
+				{Name: "util.GO", Size: 125},
+			}},
+			{Name: "README.md", Size: 250},
+			{Name: "Makefile", Size: 125},
+		},
+	}
+}
+
+func TestExtStats(t *testing.T) {
+	stats := treego.ExtStats(statsTree())
+	want := []treego.ExtStat{
+		{Ext: "go", Files: 2, Bytes: 625},
+		{Ext: "md", Files: 1, Bytes: 250},
+		{Ext: treego.NoExt, Files: 1, Bytes: 125},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("Expected %d extensions, got %+v", len(want), stats)
+	}
+	for i := range want {
+		if stats[i] != want[i] {
+			t.Errorf("stats[%d] = %+v; want %+v", i, stats[i], want[i])
+		}
+	}
+}
+
+func TestExtGroups(t *testing.T) {
+	groups, err := treego.ParseExtGroups([]string{"jpg=image, .JPEG=image", "yml=yaml"})
+	if err != nil {
+		t.Fatalf("ParseExtGroups failed: %v", err)
+	}
+	if len(groups) != 3 || groups["jpeg"] != "image" || groups["yml"] != "yaml" {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+	for _, bad := range []string{"jpg", "=image", "jpg="} {
+		if _, err := treego.ParseExtGroups([]string{bad}); err == nil {
+			t.Errorf("ParseExtGroups(%q) succeeded; want an error", bad)
+		}
+	}
+
+	root := &treego.Node{Name: "root", IsDir: true, Children: []*treego.Node{
+		{Name: "a.jpg", Size: 100},
+		{Name: "b.JPEG", Size: 50},
+		{Name: "c.yml", Size: 30},
+		{Name: "d.yaml", Size: 20},
+		{Name: "e.png", Size: 10},
+	}}
+	got := treego.GroupedExtStats(root, groups)
+	want := []treego.ExtStat{
+		{Ext: "image", Files: 2, Bytes: 150},
+		{Ext: "yaml", Files: 2, Bytes: 50},
+		{Ext: "png", Files: 1, Bytes: 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d types, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stats[%d] = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+
+	out := renderTreeOf(t, root, treego.Options{CollapseExt: 1, ExtGroups: groups})
+	if !strings.Contains(out, "... and 1 more image\n") || !strings.Contains(out, "... and 1 more yaml\n") {
+		t.Errorf("Expected grouped collapse summaries, got:\n%s", out)
+	}
+}
+
+func TestPrintComposition(t *testing.T) {
+	t.Run("textual breakdown without color", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintComposition(&buf, statsTree(), treego.Options{Color: treego.ColorNever}); err != nil {
+			t.Fatalf("PrintComposition failed: %v", err)
+		}
+		want := "go 62.5%  md 25.0%  (none) 12.5%\n"
+		if buf.String() != want {
+			t.Errorf("Expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("colored bar", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintComposition(&buf, statsTree(), treego.Options{Color: treego.ColorAlways}); err != nil {
+			t.Fatalf("PrintComposition failed: %v", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "\x1b[") || !strings.Contains(out, strings.Repeat("█", 25)+"\x1b[0m go") {
+			t.Errorf("Expected colored 25-cell go segment, got %q", out)
+		}
+	})
+
+	t.Run("small extensions fold into other", func(t *testing.T) {
+		root := &treego.Node{Name: "root", IsDir: true}
+		for _, name := range []string{"a.a", "b.b", "c.c", "d.d", "e.e", "f.f"} {
+			root.Children = append(root.Children, &treego.Node{Name: name, Size: 10})
+		}
+		var buf bytes.Buffer
+		if err := treego.PrintComposition(&buf, root, treego.Options{Color: treego.ColorNever}); err != nil {
+			t.Fatalf("PrintComposition failed: %v", err)
+		}
+		if !strings.HasSuffix(buf.String(), "other 33.3%\n") {
+			t.Errorf("Expected remaining types folded into other, got %q", buf.String())
+		}
+	})
+
+	t.Run("empty tree prints nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := treego.PrintComposition(&buf, &treego.Node{Name: "root", IsDir: true}, treego.Options{}); err != nil {
+			t.Fatalf("PrintComposition failed: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no output, got %q", buf.String())
+		}
+	})
+}
+
+func TestDepthExtremes(t *testing.T) {
+	e := treego.FindDepthExtremes(statsTree())
+	want := treego.DepthExtremes{
+		Files:        4,
+		Deepest:      "src/main.go",
+		MaxDepth:     2,
+		Shallowest:   "README.md",
+		MinDepth:     1,
+		AverageDepth: 1.5,
+	}
+	if e != want {
+		t.Errorf("FindDepthExtremes = %+v; want %+v", e, want)
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintDepthExtremes(&buf, e); err != nil {
+		t.Fatalf("PrintDepthExtremes failed: %v", err)
+	}
+	out := buf.String()
+	for _, line := range []string{"Deepest:    src/main.go (depth 2)", "Shallowest: README.md (depth 1)", "Average:    1.50 across 4 files"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected %q in output, got:\n%s", line, out)
+		}
+	}
+
+	buf.Reset()
+	if err := treego.PrintDepthExtremes(&buf, treego.FindDepthExtremes(&treego.Node{Name: "root", IsDir: true})); err != nil {
+		t.Fatalf("PrintDepthExtremes failed: %v", err)
+	}
+	if buf.String() != "No files found\n" {
+		t.Errorf("Expected empty-tree message, got %q", buf.String())
+	}
+}
+
+func TestSizeHistogram(t *testing.T) {
+	root := &treego.Node{Name: "root", IsDir: true}
+	for _, size := range []int64{1500, 2000, 9999, 250000, 10000} {
+		root.Children = append(root.Children, &treego.Node{Name: "f", Size: size})
+	}
+	got := treego.SizeHistogram(root)
+	want := []treego.SizeBucket{
+		{Min: 1000, Max: 10000, Files: 3},
+		{Min: 10000, Max: 100000, Files: 1},
+		{Min: 100000, Max: 1000000, Files: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d buckets, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := treego.PrintSizeHistogram(&buf, got); err != nil {
+		t.Fatalf("PrintSizeHistogram failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "   1K – 10K   "+strings.Repeat("█", 30)+" 3" || lines[2] != " 100K – 1M    "+strings.Repeat("█", 10)+" 1" {
+		t.Errorf("Unexpected histogram:\n%s", buf.String())
+	}
+
+	if treego.SizeHistogram(&treego.Node{Name: "root", IsDir: true}) != nil {
+		t.Error("Expected no buckets for an empty tree")
+	}
+}
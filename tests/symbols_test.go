@@ -0,0 +1,85 @@
+package treego_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestExtractSymbols(t *testing.T) {
+	dir := t.TempDir()
+	src := `package demo
+
+type Store[K comparable] struct{}
+
+func (s *Store[K]) Get(k K) {}
+
+func New() *Store[string] { return nil }
+
+var ignored = 1
+`
+	for name, body := range map[string]string{"store.go": src, "broken.go": "package demo\nfunc {", "notes.txt": "func Nope()"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resetGlobalState()
+	root := treego.BuildTreeSafe(dir)
+	treego.ExtractSymbols(root)
+
+	var store *treego.Node
+	for _, c := range root.Children {
+		if c.Name == "store.go" {
+			store = c
+		} else if len(c.Symbols) > 0 {
+			t.Errorf("%s: expected no symbols, got %v", c.Name, c.Symbols)
+		}
+	}
+	want := []treego.Symbol{{Kind: "type", Name: "Store", Line: 3}, {Kind: "method", Name: "Store.Get", Line: 5}, {Kind: "func", Name: "New", Line: 7}}
+	if len(store.Symbols) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, store.Symbols)
+	}
+	for i := range want {
+		if store.Symbols[i] != want[i] {
+			t.Errorf("Symbol %d: expected %v, got %v", i, want[i], store.Symbols[i])
+		}
+	}
+
+	root.Children = []*treego.Node{store}
+	out := renderTreeOf(t, root, treego.Options{ShowSymbols: true, RootLabel: "root"})
+	wantOut := "root\n└── store.go\n    ├── type Store\n    ├── method Store.Get\n    └── func New\n"
+	if out != wantOut {
+		t.Errorf("Expected:\n%s\ngot:\n%s", wantOut, out)
+	}
+
+	t.Run("special files are not read", func(t *testing.T) {
+		dir := t.TempDir()
+		// Opening a FIFO would block until a writer shows up.
+		mkfifo(t, filepath.Join(dir, "pipe.go"))
+		resetGlobalState()
+		root := treego.BuildTreeSafe(dir)
+		withinTimeout(t, func() { treego.ExtractSymbols(root) })
+		if len(root.Children) != 1 || root.Children[0].Symbols != nil {
+			t.Errorf("Expected pipe.go without symbols, got %+v", root.Children)
+		}
+	})
+}
+
+func TestRegisterSymbolExtractor(t *testing.T) {
+	treego.RegisterSymbolExtractor(".TODO", treego.SymbolExtractorFunc(func(path string) ([]treego.Symbol, error) {
+		return []treego.Symbol{{Kind: "note", Name: filepath.Base(path), Line: 1}}, nil
+	}))
+	e, ok := treego.LookupSymbolExtractor("list.todo")
+	if !ok {
+		t.Fatal("Expected the extractor to be found case-insensitively")
+	}
+	if syms, _ := e.Symbols("/x/list.todo"); len(syms) != 1 || !strings.HasPrefix(syms[0].Name, "list") {
+		t.Errorf("Unexpected symbols %v", syms)
+	}
+	if _, ok := treego.LookupSymbolExtractor("README"); ok {
+		t.Error("Expected no extractor for a file without extension")
+	}
+}
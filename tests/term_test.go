@@ -0,0 +1,171 @@
+package treego_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestColorMode(t *testing.T) {
+	for in, want := range map[string]treego.ColorMode{"": treego.ColorAuto, "auto": treego.ColorAuto, "always": treego.ColorAlways, "never": treego.ColorNever} {
+		got, err := treego.ParseColorMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseColorMode(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := treego.ParseColorMode("sometimes"); err == nil {
+		t.Error("Expected error for invalid color mode")
+	}
+}
+
+func TestNonTTYFallback(t *testing.T) {
+	visual := treego.Options{Color: treego.ColorAuto, Icons: true, Hyperlinks: true}
+
+	t.Run("buffer is not a terminal", func(t *testing.T) {
+		var buf bytes.Buffer
+		if treego.IsTerminal(&buf) || treego.ColorEnabled(&buf, treego.ColorAuto) {
+			t.Error("Expected buffer to be treated as non-TTY")
+		}
+		if err := treego.Render(sampleTree(), &buf, "tree", visual); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		out := buf.String()
+		if strings.Contains(out, "\x1b") {
+			t.Errorf("Expected no escape codes in non-TTY output, got %q", out)
+		}
+		if strings.Contains(out, "📁") || strings.Contains(out, "📄") {
+			t.Errorf("Expected no icons in non-TTY output, got %q", out)
+		}
+	})
+
+	t.Run("pipe is not a terminal", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Failed to create pipe: %v", err)
+		}
+		defer r.Close()
+		if treego.IsTerminal(w) {
+			t.Error("Expected pipe to be treated as non-TTY")
+		}
+		if err := treego.Render(sampleTree(), w, "tree", visual); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		w.Close()
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		if strings.Contains(buf.String(), "\x1b") {
+			t.Errorf("Expected no escape codes in piped output, got %q", buf.String())
+		}
+	})
+
+	t.Run("never wins over features", func(t *testing.T) {
+		opts := visual
+		opts.Color = treego.ColorNever
+		var buf bytes.Buffer
+		if err := treego.Render(sampleTree(), &buf, "tree", opts); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if strings.Contains(buf.String(), "\x1b") {
+			t.Errorf("Expected plain output with ColorNever, got %q", buf.String())
+		}
+	})
+
+	t.Run("always forces visual features", func(t *testing.T) {
+		opts := visual
+		opts.Color = treego.ColorAlways
+		var buf bytes.Buffer
+		if err := treego.Render(sampleTree(), &buf, "tree", opts); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "\x1b[1;34m") || !strings.Contains(out, "\x1b]8;;file://") || !strings.Contains(out, "📁") {
+			t.Errorf("Expected colors, hyperlinks and icons with ColorAlways, got %q", out)
+		}
+	})
+
+	t.Run("NO_COLOR disables auto", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		if treego.ColorEnabled(os.Stdout, treego.ColorAuto) {
+			t.Error("Expected NO_COLOR to disable auto color")
+		}
+	})
+	t.Run("always is plain in regular files unless forced", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if treego.ColorEnabled(f, treego.ColorAlways) {
+			t.Error("Expected ColorAlways to be disabled for a regular file")
+		}
+		if !treego.ColorEnabled(f, treego.ColorForce) {
+			t.Error("Expected ColorForce to enable color for a regular file")
+		}
+		if !treego.ColorEnabled(&bytes.Buffer{}, treego.ColorAlways) {
+			t.Error("Expected ColorAlways to stay enabled for non-file writers")
+		}
+		if got := treego.ResolveColor(f, treego.ColorAlways); got != treego.ColorNever {
+			t.Errorf("ResolveColor(file, always) = %v; want ColorNever", got)
+		}
+		if got := treego.ResolveColor(&bytes.Buffer{}, treego.ColorAlways); got != treego.ColorForce {
+			t.Errorf("ResolveColor(buffer, always) = %v; want ColorForce", got)
+		}
+	})
+}
+
+func TestHeatmap(t *testing.T) {
+	root := sampleTree()
+	root.Children[0].Children[0].Size = 1 << 20 // main.go, the largest
+	root.Children[1].Size = 1
+
+	colored := renderTreeOf(t, root, treego.Options{Heatmap: true, Color: treego.ColorAlways})
+	if !strings.Contains(colored, "\x1b[38;2;255;0;0mmain.go\x1b[0m") {
+		t.Errorf("Expected largest file in red, got %q", colored)
+	}
+	if !strings.Contains(colored, "0 B ") || !strings.Contains(colored, " 1.0 MiB\n") {
+		t.Errorf("Expected legend with size endpoints, got %q", colored)
+	}
+
+	plain := renderTreeOf(t, root, treego.Options{Heatmap: true, Color: treego.ColorNever})
+	if strings.Contains(plain, "\x1b") || !strings.Contains(plain, "[1.0 MiB]  main.go") {
+		t.Errorf("Expected numeric sizes without color, got %q", plain)
+	}
+}
+
+func TestZebra(t *testing.T) {
+	out := renderString(t, "tree", treego.Options{Zebra: true, Color: treego.ColorForce})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %q", out)
+	}
+	for i, l := range lines {
+		striped := strings.HasPrefix(l, "\x1b[48;5;236m") && strings.HasSuffix(l, "\x1b[K\x1b[0m")
+		if striped != (i%2 == 1) {
+			t.Errorf("line %d striped=%v: %q", i, striped, l)
+		}
+	}
+	// The directory color survives and the background is restored after it.
+	if !strings.Contains(lines[1], "\x1b[1;34msrc\x1b[0m\x1b[48;5;236m") {
+		t.Errorf("Expected directory color inside the stripe, got %q", lines[1])
+	}
+
+	if plain := renderString(t, "tree", treego.Options{Zebra: true, Color: treego.ColorNever}); strings.Contains(plain, "\x1b") {
+		t.Errorf("Expected no escapes with ColorNever, got %q", plain)
+	}
+}
+
+func TestSpinnerOnlyOnTerminals(t *testing.T) {
+	var buf bytes.Buffer
+	stop := treego.StartSpinner(&buf, "Scanning...")
+	time.Sleep(treego.SpinnerDelay + 50*time.Millisecond)
+	stop()
+	stop()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no spinner output on a buffer, got %q", buf.String())
+	}
+}
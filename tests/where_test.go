@@ -0,0 +1,86 @@
+package treego_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcuwynu23/treego/treego"
+)
+
+func TestParseWhere(t *testing.T) {
+	big := &treego.Node{Name: "Main.GO", Size: 2 << 20, ModTime: time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)}
+	small := &treego.Node{Name: "notes.md", Size: 900, ModTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)}
+	dir := &treego.Node{Name: "testdata", IsDir: true}
+
+	cases := []struct {
+		expr string
+		want [3]bool // big, small, dir
+	}{
+		{"size > 1MB", [3]bool{true, false, false}},
+		{"size >= 2MiB", [3]bool{true, false, false}},
+		{"size < 1KB", [3]bool{false, true, true}},
+		{"size == 900", [3]bool{false, true, false}},
+		{"ext == go", [3]bool{true, false, false}},
+		{"ext == .MD", [3]bool{false, true, false}},
+		{"ext != go", [3]bool{false, true, true}},
+		{"name ~ '*.md'", [3]bool{false, true, false}},
+		{`name == "Main.GO"`, [3]bool{true, false, false}},
+		{"isdir", [3]bool{false, false, true}},
+		{"!isdir && size > 1K", [3]bool{true, false, false}},
+		{"isdir == false", [3]bool{true, true, false}},
+		{"mtime >= 2024-01-01", [3]bool{true, false, false}},
+		{"mtime < 2023-06-01T00:00", [3]bool{false, true, true}},
+		{"ext == md || isdir && name ~ test*", [3]bool{false, true, true}},
+		{"(ext == md || isdir) && !(name ~ test*)", [3]bool{false, true, false}},
+		{"size > 1MB && ext == go", [3]bool{true, false, false}},
+	}
+	for _, c := range cases {
+		pred, err := treego.ParseWhere(c.expr)
+		if err != nil {
+			t.Errorf("ParseWhere(%q) failed: %v", c.expr, err)
+			continue
+		}
+		for i, n := range []*treego.Node{big, small, dir} {
+			if got := pred(n); got != c.want[i] {
+				t.Errorf("%q on %s = %v; want %v", c.expr, n.Name, got, c.want[i])
+			}
+		}
+	}
+}
+
+func TestParseWhereErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"size >",
+		"size > huge",
+		"owner == me",
+		"name < a",
+		"size ~ 1K",
+		"isdir == maybe",
+		"(ext == go",
+		"ext == go)",
+		"name == 'open",
+		"mtime > yesterday",
+		"ext == go &&",
+	} {
+		if _, err := treego.ParseWhere(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+func TestWhereKeepsAncestors(t *testing.T) {
+	pred, err := treego.ParseWhere("ext == go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := treego.Where(sampleTree(), pred)
+	if root == nil || len(root.Children) != 1 || root.Children[0].Name != "src" || root.Children[0].Children[0].Name != "main.go" {
+		t.Fatalf("Expected only root/src/main.go to remain, got %+v", root)
+	}
+
+	none, _ := treego.ParseWhere("size > 1GB")
+	if treego.Where(sampleTree(), none) != nil {
+		t.Error("Expected nil when nothing matches")
+	}
+}
@@ -0,0 +1,30 @@
+package treego
+
+// DirTotals summarizes everything below a directory.
+type DirTotals struct {
+	Dirs  int   `json:"dirs"`  // subdirectories at any depth
+	Files int   `json:"files"` // files at any depth
+	Bytes int64 `json:"bytes"` // total size of those files
+}
+
+// AggregateAll sets Totals on every directory under node in one post-order
+// pass, so each total is computed once no matter how deep the tree is, and
+// returns the totals of node's subtree (node itself included when it is a
+// file). Call it again after changing the tree.
+func AggregateAll(node *Node) DirTotals {
+	if !node.IsDir {
+		return DirTotals{Files: 1, Bytes: node.Size}
+	}
+	var t DirTotals
+	for _, c := range node.Children {
+		ct := AggregateAll(c)
+		if c.IsDir {
+			t.Dirs++
+		}
+		t.Dirs += ct.Dirs
+		t.Files += ct.Files
+		t.Bytes += ct.Bytes
+	}
+	node.Totals = &t
+	return t
+}
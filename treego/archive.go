@@ -0,0 +1,86 @@
+package treego
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+)
+
+// WriteTar streams every entry under root into a tar archive on w, with
+// slash-separated paths relative to root and the modes and modification
+// times read from disk. File contents are copied straight from disk, so
+// large files are never held in memory. The root itself is not stored;
+// a file root is archived under its own name.
+func WriteTar(root *Node, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	var walk func(n *Node, rel string) error
+	walk = func(n *Node, rel string) error {
+		if rel != "" {
+			if err := addTarEntry(tw, n, rel); err != nil {
+				return err
+			}
+		}
+		for _, c := range n.Children {
+			childRel := c.Name
+			if rel != "" {
+				childRel = rel + "/" + c.Name
+			}
+			if err := walk(c, childRel); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	rel := ""
+	if !root.IsDir {
+		rel = root.Name
+	}
+	if err := walk(root, rel); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// addTarEntry writes the header for n and, for regular files, its content.
+// A symlink the build followed (Options.FollowSymlinks) is stored as what
+// it points to, matching the children the tree lists under it; any other
+// symlink is stored as a link.
+func addTarEntry(tw *tar.Writer, n *Node, rel string) error {
+	info, err := os.Lstat(n.Path)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 && n.Mode&os.ModeSymlink == 0 {
+		info, err = os.Stat(n.Path)
+	}
+	if err != nil {
+		return err
+	}
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(n.Path); err != nil {
+			return err
+		}
+	}
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = rel
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(n.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// Copy exactly the header size so a file growing mid-read cannot
+	// overflow its entry.
+	_, err = io.CopyN(tw, f, hdr.Size)
+	return err
+}
@@ -0,0 +1,109 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// CaseCollision is a group of entries in one directory whose names are
+// equal when lower-cased, which collide on case-insensitive filesystems
+// such as the macOS and Windows defaults.
+type CaseCollision struct {
+	Dir   *Node
+	Names []string
+}
+
+// FindCaseCollisions checks every directory under root and returns the
+// colliding name groups in tree order.
+func FindCaseCollisions(root *Node) []CaseCollision {
+	var out []CaseCollision
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			return
+		}
+		groups := make(map[string][]string)
+		var order []string
+		for _, c := range n.Children {
+			key := strings.ToLower(c.Name)
+			if _, seen := groups[key]; !seen {
+				order = append(order, key)
+			}
+			groups[key] = append(groups[key], c.Name)
+		}
+		for _, key := range order {
+			if names := groups[key]; len(names) > 1 {
+				out = append(out, CaseCollision{Dir: n, Names: names})
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return out
+}
+
+// PrintCaseCollisions writes one line per collision group.
+func PrintCaseCollisions(w io.Writer, collisions []CaseCollision) error {
+	if len(collisions) == 0 {
+		_, err := fmt.Fprintln(w, "No case collisions found")
+		return err
+	}
+	for _, c := range collisions {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", c.Dir.Path, strings.Join(c.Names, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxPath is Windows' traditional MAX_PATH limit on a full path, which
+// applies unless long paths are enabled system-wide.
+const MaxPath = 260
+
+// LongPath is an entry whose full path is longer than a length limit.
+type LongPath struct {
+	Path   string // absolute
+	Length int    // in UTF-16 code units, as Windows counts
+}
+
+// FindLongPaths returns the entries under root, in tree order, whose
+// absolute path is longer than limit UTF-16 code units. Lengths are those
+// of the paths on this machine, so a Windows checkout at a longer or
+// shorter location shifts them accordingly.
+func FindLongPaths(root *Node, limit int) []LongPath {
+	base, err := filepath.Abs(root.Path)
+	if err != nil {
+		base = root.Path
+	}
+	var out []LongPath
+	var walk func(n *Node, path string)
+	walk = func(n *Node, path string) {
+		if l := len(utf16.Encode([]rune(path))); l > limit {
+			out = append(out, LongPath{Path: path, Length: l})
+		}
+		for _, c := range n.Children {
+			walk(c, filepath.Join(path, c.Name))
+		}
+	}
+	walk(root, base)
+	return out
+}
+
+// PrintLongPaths writes one "length  path" line per entry in paths.
+func PrintLongPaths(w io.Writer, paths []LongPath, limit int) error {
+	if len(paths) == 0 {
+		_, err := fmt.Fprintf(w, "No paths longer than %d characters\n", limit)
+		return err
+	}
+	for _, p := range paths {
+		if _, err := fmt.Fprintf(w, "%4d  %s\n", p.Length, p.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package treego
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges lists the code points that occupy two terminal cells: East
+// Asian wide and fullwidth forms plus the common emoji blocks.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal cells r occupies.
+func runeWidth(r rune) int {
+	if r < 0x20 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r >= rg[0] && r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// DisplayWidth returns the number of terminal cells s occupies. ANSI CSI
+// sequences (colors) and OSC sequences (hyperlinks) take no space; wide
+// characters such as CJK and emoji take two cells.
+func DisplayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b && i+1 < len(s) {
+			i = skipEscape(s, i)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// skipEscape returns the index just past the escape sequence starting at i.
+func skipEscape(s string, i int) int {
+	switch s[i+1] {
+	case '[': // CSI: parameters end with a byte in 0x40-0x7E.
+		for j := i + 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j + 1
+			}
+		}
+	case ']': // OSC: terminated by BEL or ESC \.
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+	default:
+		return i + 2
+	}
+	return len(s)
+}
+
+// TruncateWidth cuts s to at most width terminal cells, replacing the tail
+// with "..." when anything is dropped. Escape sequences are kept, and a
+// color reset is appended if one may have been cut off.
+func TruncateWidth(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	limit := width - 3
+	var b strings.Builder
+	used, escaped := 0, false
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b && i+1 < len(s) {
+			j := skipEscape(s, i)
+			b.WriteString(s[i:j])
+			escaped = true
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if used+runeWidth(r) > limit {
+			break
+		}
+		used += runeWidth(r)
+		b.WriteString(s[i : i+size])
+		i += size
+	}
+	if escaped {
+		b.WriteString(ansiReset)
+	}
+	return b.String() + "..."
+}
+
+// DrawBox writes text framed by a Unicode box sized to its widest line,
+// with title (if any) set into the top border. Empty text gives a box with
+// no lines inside.
+func DrawBox(w io.Writer, text, title string) error {
+	var lines []string
+	if text = strings.TrimRight(text, "\n"); text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	inner := 0
+	for _, l := range lines {
+		if n := DisplayWidth(l); n > inner {
+			inner = n
+		}
+	}
+	if title != "" {
+		title = " " + title + " "
+		if n := DisplayWidth(title) + 1; n > inner {
+			inner = n
+		}
+	}
+
+	ew := &errWriter{w: w}
+	// The top border is "┌─" + title + fill, so the title sits one cell in.
+	if title != "" {
+		ew.printf("┌─%s%s┐\n", title, strings.Repeat("─", inner+1-DisplayWidth(title)))
+	} else {
+		ew.printf("┌%s┐\n", strings.Repeat("─", inner+2))
+	}
+	for _, l := range lines {
+		ew.printf("│ %s%s │\n", l, strings.Repeat(" ", inner-DisplayWidth(l)))
+	}
+	ew.printf("└%s┘\n", strings.Repeat("─", inner+2))
+	return ew.err
+}
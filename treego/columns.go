@@ -0,0 +1,178 @@
+package treego
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NameColumn is the column holding the tree guides and entry names.
+const NameColumn = "name"
+
+// Column is an annotation column that can be selected with
+// Options.Columns.
+type Column struct {
+	// Value returns the cell for n; "" leaves it blank.
+	Value func(n *Node, opts Options) string
+	// Right aligns the cells to the right, as for numbers.
+	Right bool
+}
+
+var (
+	columnsMu sync.RWMutex
+	columns   = make(map[string]Column)
+)
+
+func init() {
+	RegisterColumn("size", Column{Right: true, Value: func(n *Node, opts Options) string {
+		if n.IsDir {
+			if n.Totals == nil {
+				return ""
+			}
+			return HumanizeSize(n.Totals.Bytes, opts.sizeBase())
+		}
+		return HumanizeSize(n.Size, opts.sizeBase())
+	}})
+	RegisterColumn("perms", Column{Value: func(n *Node, _ Options) string {
+		return n.Mode.String()
+	}})
+	RegisterColumn("mtime", Column{Value: func(n *Node, opts Options) string {
+		if n.ModTime.IsZero() {
+			return ""
+		}
+		return opts.formatTime(n.ModTime)
+	}})
+	RegisterColumn("atime", Column{Value: func(n *Node, opts Options) string {
+		if n.AccessTime == nil {
+			return ""
+		}
+		return opts.formatTime(*n.AccessTime)
+	}})
+	RegisterColumn("ctime", Column{Value: func(n *Node, opts Options) string {
+		if n.ChangeTime == nil {
+			return ""
+		}
+		return opts.formatTime(*n.ChangeTime)
+	}})
+	RegisterColumn("owner", Column{Value: func(n *Node, _ Options) string {
+		if n.Owner == nil {
+			return ""
+		}
+		return OwnerName(*n.Owner)
+	}})
+}
+
+// RegisterColumn makes c selectable under name. Registering a name that
+// already exists replaces the previous column.
+func RegisterColumn(name string, c Column) {
+	if name == "" || name == NameColumn {
+		panic(fmt.Sprintf("treego: RegisterColumn called with reserved name %q", name))
+	}
+	if c.Value == nil {
+		panic("treego: RegisterColumn called with nil Value for " + name)
+	}
+	columnsMu.Lock()
+	defer columnsMu.Unlock()
+	columns[name] = c
+}
+
+// LookupColumn returns the column registered under name.
+func LookupColumn(name string) (Column, bool) {
+	columnsMu.RLock()
+	defer columnsMu.RUnlock()
+	c, ok := columns[name]
+	return c, ok
+}
+
+// ColumnNames returns the registered column names, sorted, followed by
+// NameColumn.
+func ColumnNames() []string {
+	columnsMu.RLock()
+	names := make([]string, 0, len(columns)+1)
+	for name := range columns {
+		names = append(names, name)
+	}
+	columnsMu.RUnlock()
+	sort.Strings(names)
+	return append(names, NameColumn)
+}
+
+// ParseColumns parses a comma-separated column list such as
+// "size,mtime,name". Every name must be registered or NameColumn, and may
+// appear once; NameColumn is appended when it is not listed.
+func ParseColumns(spec string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := LookupColumn(name); !ok && name != NameColumn {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(ColumnNames(), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column %q listed twice", name)
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	if !seen[NameColumn] {
+		out = append(out, NameColumn)
+	}
+	return out, nil
+}
+
+// tableRow is a tree line buffered for column alignment; node is the
+// entry it shows, or nil for lines such as breadcrumbs.
+type tableRow struct {
+	node *Node
+	text string
+}
+
+// flushTable writes the buffered rows with the cells of each column in
+// Options.Columns padded to the column's width. Columns after NameColumn
+// follow the tree text, which is padded to its widest line.
+func (p *treePrinter) flushTable() error {
+	rows := p.rows
+	p.rows = nil
+	cols := make([]Column, len(p.opts.Columns))
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(cols))
+	for i, name := range p.opts.Columns {
+		cols[i], _ = LookupColumn(name)
+	}
+	for r, row := range rows {
+		cells[r] = make([]string, len(cols))
+		for i, name := range p.opts.Columns {
+			if name == NameColumn {
+				cells[r][i] = row.text
+			} else if row.node != nil {
+				cells[r][i] = cols[i].Value(row.node, p.opts)
+			}
+			widths[i] = max(widths[i], DisplayWidth(cells[r][i]))
+		}
+	}
+	last := len(cols) - 1
+	for r := range rows {
+		var b strings.Builder
+		for i, cell := range cells[r] {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-DisplayWidth(cell))
+			if cols[i].Right {
+				b.WriteString(pad + cell)
+			} else if i == last {
+				b.WriteString(cell)
+			} else {
+				b.WriteString(cell + pad)
+			}
+		}
+		if err := p.write(strings.TrimRight(b.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
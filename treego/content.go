@@ -0,0 +1,52 @@
+package treego
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ContentLimit restricts which files the content-reading features read:
+// content search, hashing (HashFiles, manifests, VerifyManifest and
+// TreeHash), DetectSignatures, ExtractSymbols and the README and file
+// previews. Files outside the limit stay in the tree but are not opened.
+type ContentLimit struct {
+	// Extensions lists the extensions of the files that may be read,
+	// without the dot and case-insensitively; empty allows every file.
+	Extensions []string
+	// MaxSize is the size above which files are not read; 0 disables it.
+	MaxSize int64
+}
+
+// Allows reports whether l permits reading the file n.
+func (l ContentLimit) Allows(n *Node) bool {
+	if l.MaxSize > 0 && n.Size > l.MaxSize {
+		return false
+	}
+	if len(l.Extensions) == 0 {
+		return true
+	}
+	ext := strings.TrimPrefix(filepath.Ext(n.Name), ".")
+	for _, want := range l.Extensions {
+		if strings.EqualFold(strings.TrimPrefix(want, "."), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// LimitContent sets SkipContent on every file under root that l does not
+// allow and clears it on the others. Run it before the content-reading
+// features.
+func LimitContent(root *Node, l ContentLimit) {
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			n.SkipContent = !l.Allows(n)
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+}
@@ -0,0 +1,256 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies an entry in a tree diff.
+type ChangeKind int
+
+const (
+	// Unchanged marks directories kept only because a descendant changed.
+	Unchanged ChangeKind = iota
+	Added
+	Removed
+	Modified
+	// Renamed marks a file that moved or changed name with its content
+	// intact; see DetectRenames.
+	Renamed
+)
+
+// marker returns the annotation printed in front of a changed entry.
+func (k ChangeKind) marker() string {
+	switch k {
+	case Added:
+		return "[+] "
+	case Removed:
+		return "[-] "
+	case Modified:
+		return "[~] "
+	case Renamed:
+		return "[>] "
+	default:
+		return ""
+	}
+}
+
+// Delta is a pruned tree holding only changed entries and their ancestors.
+type Delta struct {
+	Name   string
+	IsDir  bool
+	Change ChangeKind
+	Node   *Node // the entry in the new tree, or the old one when removed
+	// From is the old slash-separated path of a Renamed entry, relative to
+	// the root.
+	From     string
+	Children []*Delta
+}
+
+// DiffTrees compares the children of oldRoot and newRoot recursively and
+// returns the pruned delta, or nil when nothing changed. Entries are matched
+// by name; a file is modified when its size or modification time differs.
+// The root names themselves are not compared, so a snapshot taken under a
+// different path still lines up.
+func DiffTrees(oldRoot, newRoot *Node) *Delta {
+	children := diffChildren(oldRoot, newRoot)
+	if len(children) == 0 {
+		return nil
+	}
+	return &Delta{Name: newRoot.Name, IsDir: true, Node: newRoot, Children: children}
+}
+
+func diffChildren(oldDir, newDir *Node) []*Delta {
+	type key struct {
+		name  string
+		isDir bool
+	}
+	oldByKey := make(map[key]*Node, len(oldDir.Children))
+	for _, c := range oldDir.Children {
+		oldByKey[key{c.Name, c.IsDir}] = c
+	}
+
+	var out []*Delta
+	seen := make(map[key]bool, len(newDir.Children))
+	for _, n := range newDir.Children {
+		k := key{n.Name, n.IsDir}
+		seen[k] = true
+		o, ok := oldByKey[k]
+		switch {
+		case !ok:
+			out = append(out, wholeDelta(n, Added))
+		case n.IsDir:
+			if sub := diffChildren(o, n); len(sub) > 0 {
+				out = append(out, &Delta{Name: n.Name, IsDir: true, Node: n, Children: sub})
+			}
+		case o.Size != n.Size || !o.ModTime.Equal(n.ModTime):
+			out = append(out, &Delta{Name: n.Name, Change: Modified, Node: n})
+		}
+	}
+	for _, o := range oldDir.Children {
+		if !seen[key{o.Name, o.IsDir}] {
+			out = append(out, wholeDelta(o, Removed))
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return entryLess(out[i].IsDir, out[i].Name, out[j].IsDir, out[j].Name)
+	})
+	return out
+}
+
+// wholeDelta marks node and its entire subtree with kind.
+func wholeDelta(node *Node, kind ChangeKind) *Delta {
+	d := &Delta{Name: node.Name, IsDir: node.IsDir, Change: kind, Node: node}
+	for _, c := range node.Children {
+		d.Children = append(d.Children, wholeDelta(c, kind))
+	}
+	return d
+}
+
+// DeltaCounts returns the number of added, removed, modified and renamed
+// entries.
+func DeltaCounts(d *Delta) (added, removed, modified, renamed int) {
+	if d == nil {
+		return 0, 0, 0, 0
+	}
+	switch d.Change {
+	case Added:
+		added++
+	case Removed:
+		removed++
+	case Modified:
+		modified++
+	case Renamed:
+		renamed++
+	}
+	for _, c := range d.Children {
+		a, r, m, n := DeltaCounts(c)
+		added += a
+		removed += r
+		modified += m
+		renamed += n
+	}
+	return added, removed, modified, renamed
+}
+
+// DetectRenames rewrites d so that a removed file and an added file with
+// the same content are reported as one Renamed entry at the new location,
+// instead of a separate removal and addition. Content matches when both
+// files have the same size and Hash (see HashFiles). Without hashes, only
+// case-only renames (paths equal but for case) are detected, from an equal
+// size and modification time. Only files are paired. Directories that no
+// longer hold changes are pruned, and nil is returned when nothing is left.
+func DetectRenames(d *Delta) *Delta {
+	if d == nil {
+		return nil
+	}
+	type entry struct {
+		delta *Delta
+		rel   string
+	}
+	var added, removed []entry
+	var walk func(p *Delta, dir string)
+	walk = func(p *Delta, dir string) {
+		for _, c := range p.Children {
+			rel := c.Name
+			if dir != "" {
+				rel = dir + "/" + c.Name
+			}
+			if !c.IsDir {
+				switch c.Change {
+				case Added:
+					added = append(added, entry{c, rel})
+				case Removed:
+					removed = append(removed, entry{c, rel})
+				}
+			}
+			walk(c, rel)
+		}
+	}
+	walk(d, "")
+
+	same := func(a, r entry) bool {
+		an, rn := a.delta.Node, r.delta.Node
+		if an.Size != rn.Size {
+			return false
+		}
+		if an.Hash != "" && rn.Hash != "" {
+			return an.Hash == rn.Hash
+		}
+		return strings.EqualFold(a.rel, r.rel) && an.ModTime.Equal(rn.ModTime)
+	}
+	gone := make(map[*Delta]bool)
+	for _, a := range added {
+		for i, r := range removed {
+			if r.delta == nil || !same(a, r) {
+				continue
+			}
+			a.delta.Change, a.delta.From = Renamed, r.rel
+			gone[r.delta] = true
+			removed[i].delta = nil
+			break
+		}
+	}
+	if len(gone) == 0 {
+		return d
+	}
+	if !pruneDelta(d, gone) {
+		return nil
+	}
+	return d
+}
+
+// pruneDelta drops the children of d in gone, then unchanged directories
+// left without children. It reports whether d itself is still needed.
+func pruneDelta(d *Delta, gone map[*Delta]bool) bool {
+	kept := d.Children[:0]
+	for _, c := range d.Children {
+		if !gone[c] && pruneDelta(c, gone) {
+			kept = append(kept, c)
+		}
+	}
+	d.Children = kept
+	return d.Change != Unchanged || len(kept) > 0
+}
+
+// PrintDelta writes d as an annotated tree: "[+]" added, "[-]" removed,
+// "[~]" modified and "[>]" renamed, followed by the old path. Colors follow
+// opts.Color.
+func PrintDelta(w io.Writer, d *Delta, opts Options) error {
+	if d == nil {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+	st := newStyle(w, opts)
+	label := d.Name
+	if opts.RootLabel != "" {
+		label = opts.RootLabel
+	}
+	if _, err := fmt.Fprintln(w, label); err != nil {
+		return err
+	}
+	return printDeltaChildren(w, d, "", st)
+}
+
+func printDeltaChildren(w io.Writer, d *Delta, prefix string, st style) error {
+	for i, c := range d.Children {
+		branch, nextPrefix := "├── ", prefix+"│   "
+		if i == len(d.Children)-1 {
+			branch, nextPrefix = "└── ", prefix+"    "
+		}
+		label := c.Name
+		if c.Change == Renamed {
+			label += st.guide(" (from " + c.From + ")")
+		}
+		if _, err := fmt.Fprintln(w, st.guide(prefix+branch)+st.change(c.Change)+label); err != nil {
+			return err
+		}
+		if err := printDeltaChildren(w, c, nextPrefix, st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
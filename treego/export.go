@@ -0,0 +1,409 @@
+package treego
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errWriter remembers the first write error so exporters can emit many
+// small pieces without checking every call.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+// TreeToJSON writes node and all of its descendants as indented JSON.
+func TreeToJSON(node *Node, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(node)
+}
+
+// ndjsonRecord is the flat per-entry form written by TreeToNDJSON.
+type ndjsonRecord struct {
+	ID       string `json:"id,omitempty"`
+	ParentID string `json:"parentId,omitempty"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	IsDir    bool   `json:"isDir"`
+}
+
+// TreeToNDJSON writes one JSON object per entry, parents before children.
+// When IDs are assigned, each record also carries its parent's ID so the
+// hierarchy can be rebuilt.
+func TreeToNDJSON(node *Node, w io.Writer) error {
+	return writeNDJSONNode(json.NewEncoder(w), node, "")
+}
+
+func writeNDJSONNode(enc *json.Encoder, node *Node, parentID string) error {
+	rec := ndjsonRecord{ID: node.ID, ParentID: parentID, Name: node.Name, Path: node.Path, IsDir: node.IsDir}
+	if err := enc.Encode(rec); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := writeNDJSONNode(enc, child, node.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TreeToYAML writes node as a YAML document. Strings are always
+// double-quoted so names containing YAML syntax stay unambiguous.
+func TreeToYAML(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	writeYAMLNode(ew, node, "", "")
+	return ew.err
+}
+
+func writeYAMLNode(ew *errWriter, node *Node, first, indent string) {
+	if node.ID != "" {
+		ew.printf("%sid: %s\n", first, strconv.Quote(node.ID))
+		first = indent
+	}
+	ew.printf("%sname: %s\n", first, strconv.Quote(node.Name))
+	ew.printf("%spath: %s\n", indent, strconv.Quote(node.Path))
+	ew.printf("%sisDir: %t\n", indent, node.IsDir)
+	if len(node.Children) == 0 {
+		return
+	}
+	ew.printf("%schildren:\n", indent)
+	for _, child := range node.Children {
+		writeYAMLNode(ew, child, indent+"  - ", indent+"    ")
+	}
+}
+
+// TreeToHTML writes node as a standalone HTML page containing nested lists.
+func TreeToHTML(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	title := html.EscapeString(node.Name)
+	ew.printf("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n<ul>\n", title)
+	writeHTMLNode(ew, node, "")
+	ew.printf("</ul>\n</body>\n</html>\n")
+	return ew.err
+}
+
+func writeHTMLNode(ew *errWriter, node *Node, indent string) {
+	name := html.EscapeString(node.Name)
+	if !node.IsDir {
+		ew.printf("%s<li>%s</li>\n", indent, name)
+		return
+	}
+	if len(node.Children) == 0 {
+		ew.printf("%s<li class=\"dir\">%s/</li>\n", indent, name)
+		return
+	}
+	ew.printf("%s<li class=\"dir\">%s/\n%s<ul>\n", indent, name, indent)
+	for _, child := range node.Children {
+		writeHTMLNode(ew, child, indent+"  ")
+	}
+	ew.printf("%s</ul>\n%s</li>\n", indent, indent)
+}
+
+// TreeToDOT writes node as a Graphviz digraph. Node IDs are assigned in
+// depth-first order so the output is stable for a given tree.
+func TreeToDOT(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph treego {\n  rankdir=LR;\n  node [fontname=\"Helvetica\"];\n")
+	next := 0
+	writeDOTNode(ew, node, &next)
+	ew.printf("}\n")
+	return ew.err
+}
+
+func writeDOTNode(ew *errWriter, node *Node, next *int) int {
+	id := *next
+	*next++
+	shape := "note"
+	if node.IsDir {
+		shape = "folder"
+	}
+	ew.printf("  n%d [label=%s, shape=%s];\n", id, dotQuote(node.Name), shape)
+	for _, child := range node.Children {
+		childID := writeDOTNode(ew, child, next)
+		ew.printf("  n%d -> n%d;\n", id, childID)
+	}
+	return id
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// TreeToPlantUML writes node as a PlantUML diagram: directories become
+// packages nesting their contents and files become components. Elements get
+// aliases n0, n1, ... in depth-first order, and names are quoted with
+// characters PlantUML would interpret replaced by <U+XXXX> escapes.
+func TreeToPlantUML(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("@startuml\n")
+	next := 0
+	writePlantUMLNode(ew, node, "", &next)
+	ew.printf("@enduml\n")
+	return ew.err
+}
+
+func writePlantUMLNode(ew *errWriter, node *Node, indent string, next *int) {
+	id := *next
+	*next++
+	if !node.IsDir {
+		ew.printf("%scomponent %s as n%d\n", indent, plantUMLQuote(node.Name), id)
+		return
+	}
+	ew.printf("%spackage %s as n%d {\n", indent, plantUMLQuote(node.Name), id)
+	for _, child := range node.Children {
+		writePlantUMLNode(ew, child, indent+"  ", next)
+	}
+	ew.printf("%s}\n", indent)
+}
+
+var plantUMLEscaper = strings.NewReplacer(
+	`"`, "<U+0022>", `\`, "<U+005C>", "<", "<U+003C>", "~", "<U+007E>", "\n", " ",
+)
+
+func plantUMLQuote(s string) string {
+	return `"` + plantUMLEscaper.Replace(s) + `"`
+}
+
+// TreeToTreeJSON writes node in the JSON format of tree -J: an array
+// holding the root entry, with "type" (directory, file or link), "name"
+// and, for directories, "contents", followed by a report object counting
+// the directories (excluding the root) and files. Symlinks that were not
+// followed carry their "target". The root is named by its path, as tree
+// names it by the argument it was given.
+func TreeToTreeJSON(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	name := node.Path
+	if name == "" {
+		name = node.Name
+	}
+	var dirs, files int
+	ew.printf("[\n")
+	writeTreeJSONNode(ew, node, name, "  ", &dirs, &files)
+	ew.printf("\n,\n  {\"type\":\"report\",\"directories\":%d,\"files\":%d}\n]\n", dirs, files)
+	return ew.err
+}
+
+func writeTreeJSONNode(ew *errWriter, node *Node, name, indent string, dirs, files *int) {
+	quoted := jsonString(name)
+	switch {
+	case node.LinkTarget != "":
+		*files++
+		ew.printf("%s{\"type\":\"link\",\"name\":%s,\"target\":%s}", indent, quoted, jsonString(node.LinkTarget))
+		return
+	case !node.IsDir:
+		*files++
+		ew.printf("%s{\"type\":\"file\",\"name\":%s}", indent, quoted)
+		return
+	}
+	ew.printf("%s{\"type\":\"directory\",\"name\":%s,\"contents\":[", indent, quoted)
+	for i, child := range node.Children {
+		if child.IsDir {
+			*dirs++
+		}
+		if i > 0 {
+			ew.printf(",")
+		}
+		ew.printf("\n")
+		writeTreeJSONNode(ew, child, child.Name, indent+"  ", dirs, files)
+	}
+	if len(node.Children) > 0 {
+		ew.printf("\n%s", indent)
+	}
+	ew.printf("]}")
+}
+
+// jsonString returns s as a JSON string literal, leaving <, > and & as is.
+func jsonString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// TreeToCSV writes one row per entry with the columns path, name and type.
+func TreeToCSV(node *Node, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"path", "name", "type"}); err != nil {
+		return err
+	}
+	if err := writeCSVNode(cw, node); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeCSVNode(cw *csv.Writer, node *Node) error {
+	kind := "file"
+	if node.IsDir {
+		kind = "dir"
+	}
+	if err := cw.Write([]string{node.Path, node.Name, kind}); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := writeCSVNode(cw, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TreeToMarkdown writes node as a nested Markdown bullet list.
+// Directory names end with a slash.
+func TreeToMarkdown(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	writeMarkdownNode(ew, node, "")
+	return ew.err
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func writeMarkdownNode(ew *errWriter, node *Node, indent string) {
+	name := markdownEscaper.Replace(node.Name)
+	if node.IsDir {
+		name += "/"
+	}
+	ew.printf("%s- %s\n", indent, name)
+	for _, child := range node.Children {
+		writeMarkdownNode(ew, child, indent+"  ")
+	}
+}
+
+// TreeToRST writes node as a nested reStructuredText bullet list, as used
+// by Sphinx. Items are separated by blank lines and children are indented
+// to line up with their parent's text, so every level renders as a nested
+// list. Directory names end with a slash.
+func TreeToRST(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	writeRSTNode(ew, node, "")
+	return ew.err
+}
+
+var rstEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "`", "\\`", "|", `\|`, "_", `\_`,
+)
+
+func writeRSTNode(ew *errWriter, node *Node, indent string) {
+	name := rstEscaper.Replace(node.Name)
+	// A leading bullet or enumerator character would start a nested list.
+	if name != "" && strings.ContainsRune("-+#0123456789", rune(name[0])) {
+		name = `\` + name
+	}
+	if node.IsDir {
+		name += "/"
+	}
+	ew.printf("%s- %s\n\n", indent, name)
+	for _, child := range node.Children {
+		writeRSTNode(ew, child, indent+"  ")
+	}
+}
+
+// TreeToSQL writes node as portable SQL: a files table (id, parent_id,
+// path, name, is_dir, size, mtime) and one INSERT per entry inside a
+// transaction, so the dump loads into SQLite, PostgreSQL or MySQL without a
+// driver. IDs number entries in tree order from 1; the root's parent_id is
+// NULL and mtime is an RFC 3339 timestamp.
+func TreeToSQL(node *Node, w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("CREATE TABLE files (\n" +
+		"  id INTEGER PRIMARY KEY,\n" +
+		"  parent_id INTEGER REFERENCES files(id),\n" +
+		"  path TEXT NOT NULL,\n" +
+		"  name TEXT NOT NULL,\n" +
+		"  is_dir INTEGER NOT NULL,\n" +
+		"  size INTEGER NOT NULL,\n" +
+		"  mtime TEXT\n" +
+		");\n")
+	ew.printf("BEGIN;\n")
+	next := 0
+	var walk func(n *Node, parent string)
+	walk = func(n *Node, parent string) {
+		next++
+		id := strconv.Itoa(next)
+		isDir := 0
+		if n.IsDir {
+			isDir = 1
+		}
+		mtime := "NULL"
+		if !n.ModTime.IsZero() {
+			mtime = sqlQuote(n.ModTime.UTC().Format(time.RFC3339))
+		}
+		ew.printf("INSERT INTO files VALUES (%s, %s, %s, %s, %d, %d, %s);\n",
+			id, parent, sqlQuote(n.Path), sqlQuote(n.Name), isDir, n.Size, mtime)
+		for _, c := range n.Children {
+			walk(c, id)
+		}
+	}
+	walk(node, "NULL")
+	ew.printf("COMMIT;\n")
+	return ew.err
+}
+
+// sqlQuote returns s as a standard SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// SitemapExtensions are the file extensions TreeToSitemap lists.
+var SitemapExtensions = []string{"html", "htm"}
+
+// TreeToSitemap writes a sitemaps.org sitemap of the HTML files under node,
+// treating node as the web root served at baseURL. Each file is a <url>
+// whose <loc> is baseURL joined with its escaped relative path, and whose
+// <lastmod> is its mtime; an index.html stands for its directory's URL.
+func TreeToSitemap(node *Node, baseURL string, w io.Writer) error {
+	return TreeToSitemapExt(node, baseURL, SitemapExtensions, w)
+}
+
+// TreeToSitemapExt is TreeToSitemap listing the files with the given
+// extensions ("html" and ".html" are equivalent, case is ignored) instead
+// of SitemapExtensions.
+func TreeToSitemapExt(node *Node, baseURL string, exts []string, w io.Writer) error {
+	keep := make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		keep[strings.ToLower(strings.TrimPrefix(ext, "."))] = true
+	}
+	base := strings.TrimRight(baseURL, "/") + "/"
+	ew := &errWriter{w: w}
+	ew.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	ew.printf("<urlset xmlns=\"http://www.sitemaps.org/schemas/sitemap/0.9\">\n")
+	for _, f := range relFiles(node) {
+		if !keep[strings.ToLower(strings.TrimPrefix(filepath.Ext(f.Rel), "."))] {
+			continue
+		}
+		segs := strings.Split(f.Rel, "/")
+		if name := strings.ToLower(segs[len(segs)-1]); strings.HasPrefix(name, "index.") {
+			segs[len(segs)-1] = ""
+		}
+		for i, s := range segs {
+			segs[i] = url.PathEscape(s)
+		}
+		ew.printf("  <url>\n    <loc>%s</loc>\n", html.EscapeString(base+strings.Join(segs, "/")))
+		if !f.Node.ModTime.IsZero() {
+			ew.printf("    <lastmod>%s</lastmod>\n", f.Node.ModTime.UTC().Format(time.RFC3339))
+		}
+		ew.printf("  </url>\n")
+	}
+	ew.printf("</urlset>\n")
+	return ew.err
+}
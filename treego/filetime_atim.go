@@ -0,0 +1,18 @@
+//go:build linux || openbsd || dragonfly || solaris
+
+package treego
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the access and status change times recorded in info.
+func fileTimes(info os.FileInfo) (atime, ctime time.Time, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return time.Unix(st.Atim.Unix()), time.Unix(st.Ctim.Unix()), true
+}
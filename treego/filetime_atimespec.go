@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || netbsd
+
+package treego
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the access and status change times recorded in info.
+func fileTimes(info os.FileInfo) (atime, ctime time.Time, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	return time.Unix(st.Atimespec.Unix()), time.Unix(st.Ctimespec.Unix()), true
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !openbsd && !dragonfly && !solaris && !darwin && !freebsd && !netbsd
+
+package treego
+
+import (
+	"os"
+	"time"
+)
+
+// fileTimes is unavailable on this platform, so entries carry no access
+// or change time.
+func fileTimes(info os.FileInfo) (atime, ctime time.Time, ok bool) {
+	return time.Time{}, time.Time{}, false
+}
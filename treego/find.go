@@ -0,0 +1,89 @@
+package treego
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Glob is a compiled recursive glob over slash-separated relative paths,
+// as used by --find.
+type Glob struct {
+	parts []string
+}
+
+// CompileGlob parses a recursive glob such as "**/*.test.go" or
+// "cmd/**/main.go". A "**" segment matches zero or more path segments;
+// every other segment uses path.Match syntax and matches exactly one
+// segment, so "*.go" on its own only matches entries directly under the
+// root.
+func CompileGlob(pattern string) (*Glob, error) {
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid glob %q: empty path segment", pattern)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %v", pattern, err)
+		}
+	}
+	return &Glob{parts: parts}, nil
+}
+
+// Match reports whether the slash-separated relative path rel matches g.
+func (g *Glob) Match(rel string) bool {
+	return matchSegments(g.parts, strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse repeated ** and try every split point.
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range segs {
+				if matchSegments(pattern, segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// Find prunes root down to the entries whose path relative to root matches
+// any of globs, plus the directories leading to them (see PruneTree). The
+// root itself is not tested. It returns nil when nothing matches.
+func Find(root *Node, globs []*Glob) *Node {
+	matched := make(map[*Node]bool)
+	var walk func(n *Node, rel string)
+	walk = func(n *Node, rel string) {
+		for _, c := range n.Children {
+			childRel := c.Name
+			if rel != "" {
+				childRel = rel + "/" + c.Name
+			}
+			for _, g := range globs {
+				if g.Match(childRel) {
+					matched[c] = true
+					break
+				}
+			}
+			walk(c, childRel)
+		}
+	}
+	walk(root, "")
+	return PruneTree(root, func(n *Node) bool { return matched[n] })
+}
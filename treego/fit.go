@@ -0,0 +1,103 @@
+package treego
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// lineCounter is a writer that only counts newlines.
+type lineCounter int
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	*c += lineCounter(bytes.Count(p, []byte{'\n'}))
+	return len(p), nil
+}
+
+// FitDepth finds the deepest level at which root, run through the pipeline
+// and rendered in the tree format with opts, takes at most lines lines. It
+// returns 0 when the whole tree fits, and otherwise the MaxDepth to use
+// and the number of entries it hides. When even the first level is too
+// tall, depth is 1.
+func FitDepth(root *Node, opts Options, lines int) (depth, hidden int, err error) {
+	if root = PrepareTree(root, opts); root == nil {
+		return 0, 0, nil
+	}
+	full := treeDepth(root)
+	if opts.MaxDepth > 0 && opts.MaxDepth < full {
+		full = opts.MaxDepth
+	}
+	count := func(d int) (int, error) {
+		var c lineCounter
+		o := opts
+		o.MaxDepth = d
+		err := renderTree(root, &c, o)
+		return int(c), err
+	}
+	if n, err := count(full); err != nil || n <= lines || full <= 1 {
+		return 0, 0, err
+	}
+	// Line counts grow with depth, so binary search the deepest fit.
+	lo, hi := 1, full-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		n, err := count(mid)
+		if err != nil {
+			return 0, 0, err
+		}
+		if n <= lines {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, entriesBelow(root, lo, full), nil
+}
+
+// entriesBelow counts the entries deeper than depth but at most max levels
+// below n.
+func entriesBelow(n *Node, depth, max int) int {
+	count := 0
+	var walk func(n *Node, level int)
+	walk = func(n *Node, level int) {
+		for _, c := range n.Children {
+			if level+1 > max {
+				return
+			}
+			if level+1 > depth {
+				count++
+			}
+			walk(c, level+1)
+		}
+	}
+	walk(n, 0)
+	return count
+}
+
+// FitScreen renders root in the tree format, collapsing deeper levels
+// until it fits in height terminal lines, with one line reserved for a
+// note saying how much was collapsed. A height of 0 or less means unknown
+// and renders the full tree.
+func FitScreen(w io.Writer, root *Node, opts Options, height int) error {
+	if height <= 0 {
+		return RenderNode(root, w, DefaultFormat, opts)
+	}
+	depth, hidden, err := FitDepth(root, opts, height-1)
+	if err != nil {
+		return err
+	}
+	if depth == 0 {
+		return RenderNode(root, w, DefaultFormat, opts)
+	}
+	opts.MaxDepth = depth
+	if err := RenderNode(root, w, DefaultFormat, opts); err != nil {
+		return err
+	}
+	entries := "entries"
+	if hidden == 1 {
+		entries = "entry"
+	}
+	note := fmt.Sprintf("... %d %s below depth %d collapsed to fit the screen", hidden, entries, depth)
+	_, err = fmt.Fprintln(w, newStyle(w, opts).guide(note))
+	return err
+}
@@ -0,0 +1,327 @@
+package treego
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotInRepo is returned by FindRepoRoot outside of a git repository.
+var ErrNotInRepo = errors.New("not inside a git repository")
+
+// FindRepoRoot walks up from start to the nearest directory containing
+// .git (a directory, or a file as in worktrees and submodules) and returns
+// its absolute path. If start is a file, the search begins at its
+// directory.
+func FindRepoRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s: %w", start, ErrNotInRepo)
+		}
+		dir = parent
+	}
+}
+
+// Submodule is one entry of a .gitmodules file.
+type Submodule struct {
+	Name string
+	Path string // slash-separated, relative to the repository root
+	URL  string
+}
+
+// ParseGitmodules reads the [submodule "name"] sections of a .gitmodules
+// file. Entries without a path are skipped; the result is sorted by path.
+func ParseGitmodules(r io.Reader) ([]Submodule, error) {
+	var out []Submodule
+	var cur *Submodule
+	flush := func() {
+		if cur != nil && cur.Path != "" {
+			out = append(out, *cur)
+		}
+		cur = nil
+	}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			flush()
+			section := strings.TrimSpace(strings.Trim(line, "[]"))
+			if name, ok := strings.CutPrefix(section, "submodule"); ok {
+				cur = &Submodule{Name: strings.Trim(strings.TrimSpace(name), `"`)}
+			}
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf(".gitmodules line %d: malformed entry", n)
+		}
+		if cur == nil {
+			continue
+		}
+		val = strings.Trim(strings.TrimSpace(val), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "path":
+			cur.Path = strings.TrimSuffix(filepath.ToSlash(val), "/")
+		case "url":
+			cur.URL = val
+		}
+	}
+	flush()
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, sc.Err()
+}
+
+// LoadSubmodules reads .gitmodules from the root of the repository that
+// contains start. A repository without the file has no submodules.
+func LoadSubmodules(start string) (repo string, subs []Submodule, err error) {
+	repo, err = FindRepoRoot(start)
+	if err != nil {
+		return "", nil, err
+	}
+	f, err := os.Open(filepath.Join(repo, ".gitmodules"))
+	if os.IsNotExist(err) {
+		return repo, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	subs, err = ParseGitmodules(f)
+	return repo, subs, err
+}
+
+// SubmoduleDirs returns the submodules of the repository at repo whose
+// directory is part of the tree under root, in tree order.
+func SubmoduleDirs(root *Node, repo string, subs []Submodule) []Submodule {
+	byPath := make(map[string]Submodule, len(subs))
+	for _, s := range subs {
+		byPath[s.Path] = s
+	}
+	var out []Submodule
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			return
+		}
+		if abs, err := filepath.Abs(n.Path); err == nil {
+			if rel, err := filepath.Rel(repo, abs); err == nil {
+				if s, ok := byPath[filepath.ToSlash(rel)]; ok {
+					out = append(out, s)
+					return
+				}
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return out
+}
+
+// PrintSubmodules writes one "path  url" line per submodule.
+func PrintSubmodules(w io.Writer, subs []Submodule) error {
+	if len(subs) == 0 {
+		_, err := fmt.Fprintln(w, "No submodules found")
+		return err
+	}
+	ew := &errWriter{w: w}
+	for _, s := range subs {
+		ew.printf("%s  %s\n", s.Path, s.URL)
+	}
+	return ew.err
+}
+
+// GitCommit identifies the last commit that touched a file.
+type GitCommit struct {
+	Author string    `json:"author"`
+	Date   time.Time `json:"date"`
+}
+
+// gitCommits caches "git log -1" results by repository and path for the
+// life of the process.
+var gitCommits sync.Map // repo + "\x00" + rel -> *GitCommit
+
+// AnnotateGitAuthors sets LastCommit on every file under root that is
+// tracked by the repository at repo, asking git for each file's latest
+// commit concurrently. Untracked and ignored files are left alone. It fails
+// only when git itself cannot list the repository.
+func AnnotateGitAuthors(root *Node, repo string) error {
+	tracked, err := gitTracked(repo)
+	if err != nil {
+		return err
+	}
+	type target struct {
+		node *Node
+		rel  string
+	}
+	var targets []target
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			abs, err := filepath.Abs(n.Path)
+			if err != nil {
+				return
+			}
+			if rel, err := filepath.Rel(repo, abs); err == nil && tracked[filepath.ToSlash(rel)] {
+				targets = append(targets, target{n, filepath.ToSlash(rel)})
+			}
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	parallelEach(len(targets), func(i int) {
+		targets[i].node.LastCommit = gitLastCommit(repo, targets[i].rel)
+	})
+	return nil
+}
+
+// gitTracked returns the slash-separated paths of the files git tracks in
+// repo, relative to its root.
+func gitTracked(repo string) (map[string]bool, error) {
+	return gitLsFiles(repo)
+}
+
+// gitCommittable returns the slash-separated paths, relative to the root of
+// repo, of the files a "git add -A" would stage: tracked files and
+// untracked ones that are not ignored.
+func gitCommittable(repo string) (map[string]bool, error) {
+	return gitLsFiles(repo, "--cached", "--others", "--exclude-standard")
+}
+
+// gitLsFiles runs "git ls-files" with args in repo and returns the listed
+// paths relative to its root.
+func gitLsFiles(repo string, args ...string) (map[string]bool, error) {
+	cmd := append([]string{"-C", repo, "ls-files", "-z", "--full-name"}, args...)
+	out, err := exec.Command("git", cmd...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	files := make(map[string]bool)
+	for _, p := range bytes.Split(out, []byte{0}) {
+		if len(p) > 0 {
+			files[string(p)] = true
+		}
+	}
+	return files, nil
+}
+
+// gitLastCommit returns the latest commit touching rel in repo, or nil
+// when git reports none.
+func gitLastCommit(repo, rel string) *GitCommit {
+	key := repo + "\x00" + rel
+	if c, ok := gitCommits.Load(key); ok {
+		return c.(*GitCommit)
+	}
+	var commit *GitCommit
+	// rel is a file name, not a pattern: "*.go" or "[ab].txt" must not
+	// match other files.
+	out, err := exec.Command("git", "--literal-pathspecs", "-C", repo, "log", "-1", "--format=%an%x00%aI", "--", rel).Output()
+	if author, date, ok := strings.Cut(strings.TrimSpace(string(out)), "\x00"); err == nil && ok {
+		if t, err := time.Parse(time.RFC3339, date); err == nil {
+			commit = &GitCommit{Author: author, Date: t}
+		}
+	}
+	gitCommits.Store(key, commit)
+	return commit
+}
+
+// GitHub's limits on the size of a single file: pushes print a warning for
+// files larger than GitLargeFileWarn and are rejected for files larger than
+// GitLargeFileLimit.
+const (
+	GitLargeFileWarn  int64 = 50 << 20
+	GitLargeFileLimit int64 = 100 << 20
+)
+
+// LargeFile is a file larger than a size threshold.
+type LargeFile struct {
+	Path      string
+	Size      int64
+	OverLimit bool // also larger than the hard limit
+}
+
+// FindGitLargeFiles returns the files under root that the repository at
+// repo tracks or would pick up (untracked and not ignored) and that are
+// larger than warn, largest first. OverLimit is set on those larger than
+// limit. Symlinks are skipped, as git stores only their target. It fails
+// only when git itself cannot list the repository.
+func FindGitLargeFiles(root *Node, repo string, warn, limit int64) ([]LargeFile, error) {
+	files, err := gitCommittable(repo)
+	if err != nil {
+		return nil, err
+	}
+	var out []LargeFile
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n.IsDir {
+			for _, c := range n.Children {
+				walk(c)
+			}
+			return
+		}
+		if n.Size <= warn || n.Mode&os.ModeSymlink != 0 {
+			return
+		}
+		abs, err := filepath.Abs(n.Path)
+		if err != nil {
+			return
+		}
+		if rel, err := filepath.Rel(repo, abs); err == nil && files[filepath.ToSlash(rel)] {
+			out = append(out, LargeFile{Path: n.Path, Size: n.Size, OverLimit: n.Size > limit})
+		}
+	}
+	walk(root)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out, nil
+}
+
+// PrintGitLargeFiles writes one line per file, marked "error" when it is
+// over limit and "warning" otherwise, followed by a summary line.
+func PrintGitLargeFiles(w io.Writer, files []LargeFile, warn, limit int64) error {
+	if len(files) == 0 {
+		_, err := fmt.Fprintf(w, "No files larger than %s\n", HumanizeSize(warn, BinaryBase))
+		return err
+	}
+	over := 0
+	for _, f := range files {
+		level := "warning"
+		if f.OverLimit {
+			level = "error"
+			over++
+		}
+		if _, err := fmt.Fprintf(w, "%-7s  %10s  %s\n", level, HumanizeSize(f.Size, BinaryBase), f.Path); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s larger than %s, %d over the %s limit\n",
+		plural(len(files), "file"), HumanizeSize(warn, BinaryBase), over, HumanizeSize(limit, BinaryBase))
+	return err
+}
@@ -0,0 +1,148 @@
+package treego
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// binarySniffLen is how many leading bytes are inspected for NUL bytes
+// before a file is treated as binary and skipped by content matching.
+const binarySniffLen = 8000
+
+// maxLineLen bounds the line buffer used while scanning file contents.
+const maxLineLen = 1024 * 1024
+
+// LineMatch is a single matching line inside a file.
+type LineMatch struct {
+	Line int
+	Text string
+}
+
+// Match is a file selected by SearchContent together with the lines
+// that matched the content condition (empty when only the name matched).
+type Match struct {
+	Node  *Node
+	Lines []LineMatch
+}
+
+// MatchQuery combines a name condition and a content condition.
+// Only files are considered. An empty Name or nil Content disables
+// that condition.
+type MatchQuery struct {
+	// Name is a case-insensitive substring matched against the file name,
+	// like SearchDFS.
+	Name string
+	// Content is matched against each line of the file.
+	Content NameMatcher
+	// Any selects files matching either condition instead of both.
+	Any bool
+}
+
+// GrepFile returns the lines of the file at path that match m.
+// Binary files yield no matches.
+func GrepFile(path string, m NameMatcher) ([]LineMatch, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	if head, _ := br.Peek(binarySniffLen); bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	var out []LineMatch
+	sc := bufio.NewScanner(br)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineLen)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if m.MatchString(line) {
+			out = append(out, LineMatch{Line: n, Text: line})
+		}
+	}
+	return out, sc.Err()
+}
+
+// SearchContent returns the files under node that satisfy q, in tree order.
+// File contents are read concurrently; only regular files are read, so
+// symlinks, FIFOs, devices and files marked SkipContent never match the
+// content condition.
+func SearchContent(node *Node, q MatchQuery) []Match {
+	var files []*Node
+	collectFiles(node, &files)
+
+	results := make([]*Match, len(files))
+	parallelEach(len(files), func(i int) {
+		results[i] = matchFile(files[i], q)
+	})
+
+	var out []Match
+	for _, r := range results {
+		if r != nil {
+			out = append(out, *r)
+		}
+	}
+	return out
+}
+
+func collectFiles(node *Node, files *[]*Node) {
+	if !node.IsDir {
+		*files = append(*files, node)
+		return
+	}
+	for _, child := range node.Children {
+		collectFiles(child, files)
+	}
+}
+
+func matchFile(node *Node, q MatchQuery) *Match {
+	nameSet := q.Name != ""
+	contentSet := q.Content != nil
+	nameOK := nameSet && nameContains(node.Name, q.Name)
+
+	if !q.Any && nameSet && !nameOK {
+		// The AND case can skip reading the file entirely.
+		return nil
+	}
+
+	var lines []LineMatch
+	if contentSet && node.Mode.IsRegular() && !node.SkipContent {
+		lines, _ = GrepFile(node.Path, q.Content)
+	}
+	contentOK := len(lines) > 0
+
+	var ok bool
+	switch {
+	case nameSet && contentSet && q.Any:
+		ok = nameOK || contentOK
+	case nameSet && contentSet:
+		ok = nameOK && contentOK
+	case nameSet:
+		ok = nameOK
+	case contentSet:
+		ok = contentOK
+	}
+	if !ok {
+		return nil
+	}
+	return &Match{Node: node, Lines: lines}
+}
+
+// PrintMatches writes each matched path followed by its matching lines.
+func PrintMatches(w io.Writer, matches []Match) error {
+	for _, m := range matches {
+		if _, err := fmt.Fprintln(w, m.Node.Path); err != nil {
+			return err
+		}
+		for _, l := range m.Lines {
+			if _, err := fmt.Fprintf(w, "  %d: %s\n", l.Line, l.Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package treego
+
+import "io"
+
+// RenderGrouped prints each top-level directory under root as its own
+// section: a header, the directory's tree, and a line with its directory
+// count (itself included), file count and total size. Files directly under
+// root share a final section. A grand total of all sections ends the
+// output. Totals are those of AggregateAll, so filters apply but
+// opts.MaxDepth only shortens the trees.
+func RenderGrouped(w io.Writer, root *Node, opts Options) error {
+	p := newTreePrinter(w, opts)
+	AggregateAll(root)
+	opts.RootLabel = ""
+
+	var files []*Node
+	var sections int
+	ew := &errWriter{w: w}
+	section := func(title string, node *Node, t DirTotals) {
+		if ew.err != nil {
+			return
+		}
+		if sections > 0 {
+			ew.printf("\n")
+		}
+		sections++
+		ew.printf("%s\n", p.style.guide(p.guides.rule+" "+title+" "+p.guides.rule))
+		if ew.err == nil {
+			ew.err = renderTree(node, w, opts)
+		}
+		ew.printf("%d directories, %d files, %s\n", t.Dirs, t.Files, HumanizeSize(t.Bytes, opts.sizeBase()))
+	}
+	for _, c := range root.Children {
+		if !c.IsDir {
+			files = append(files, c)
+			continue
+		}
+		t := *c.Totals
+		t.Dirs++
+		section(c.Name, c, t)
+	}
+	if len(files) > 0 {
+		loose := &Node{Name: root.Name, Path: root.Path, IsDir: true, ModTime: root.ModTime, Mode: root.Mode, Children: files}
+		section("files in "+root.Name, loose, AggregateAll(loose))
+	}
+	t := root.Totals
+	ew.printf("\nTotal: %s, %d directories, %d files, %s\n",
+		plural(sections, "section"), t.Dirs, t.Files, HumanizeSize(t.Bytes, opts.sizeBase()))
+	return ew.err
+}
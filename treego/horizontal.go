@@ -0,0 +1,121 @@
+package treego
+
+import (
+	"io"
+	"strings"
+)
+
+// junctions are the characters joining a directory to its children in the
+// horizontal layout.
+type junctions struct {
+	single, top, bottom, middle string // a directory row that is also a child row
+	tee                         string // a directory row between two child rows
+	first, last, branch, pipe   string // child rows and the rows between them
+	dash                        string
+}
+
+var (
+	unicodeJunctions = junctions{single: "─", top: "┬", bottom: "┴", middle: "┼", tee: "┤", first: "┌", last: "└", branch: "├", pipe: "│", dash: "─"}
+	asciiJunctions   = junctions{single: "-", top: "+", bottom: "+", middle: "+", tee: "+", first: "+", last: "`", branch: "+", pipe: "|", dash: "-"}
+)
+
+// hBlock is the rendering of one subtree in the horizontal layout: its
+// lines, and the line its root label is on (where the parent connects).
+type hBlock struct {
+	lines  []string
+	anchor int
+}
+
+// TreeToHorizontal writes the tree laid out left to right, like an org
+// chart turned on its side: the root on the left, each directory centered
+// against its children and joined to them by box-drawing lines, leaves on
+// the right. opts.MaxDepth, ASCII and the name styling apply as for the
+// tree format.
+func TreeToHorizontal(node *Node, w io.Writer, opts Options) error {
+	p := newTreePrinter(w, opts)
+	j := unicodeJunctions
+	if opts.ASCII {
+		j = asciiJunctions
+	}
+	label := node.Name
+	if opts.RootLabel != "" {
+		label = opts.RootLabel
+	}
+	label = p.style.name(node, label)
+	if node.Truncated {
+		label += " [truncated]"
+	}
+	b := p.horizontal(node, label, 0, j)
+	for _, line := range b.lines {
+		if err := p.write(strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// horizontal lays out node, labeled label, at depth below the root.
+func (p *treePrinter) horizontal(node *Node, label string, depth int, j junctions) hBlock {
+	var children []hBlock
+	if p.opts.MaxDepth <= 0 || depth < p.opts.MaxDepth {
+		for _, c := range node.Children {
+			children = append(children, p.horizontal(c, p.label(c), depth+1, j))
+		}
+	}
+	if len(children) == 0 {
+		return hBlock{lines: []string{label}}
+	}
+
+	// Stack the children and note the row each one connects on.
+	var rows []string
+	anchors := make(map[int]bool, len(children))
+	first, last := -1, 0
+	for _, c := range children {
+		a := len(rows) + c.anchor
+		anchors[a] = true
+		if first < 0 {
+			first = a
+		}
+		last = a
+		rows = append(rows, c.lines...)
+	}
+	anchor := (first + last) / 2
+
+	pad := strings.Repeat(" ", DisplayWidth(label)+2)
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		prefix := pad
+		if r == anchor {
+			prefix = label + " " + p.style.guide(j.dash)
+		}
+		var joint string
+		switch {
+		case len(children) == 1:
+			joint = j.single
+		case r == anchor && r == first:
+			joint = j.top
+		case r == anchor && r == last:
+			joint = j.bottom
+		case r == anchor && anchors[r]:
+			joint = j.middle
+		case r == anchor:
+			joint = j.tee
+		case r == first:
+			joint = j.first
+		case r == last:
+			joint = j.last
+		case anchors[r]:
+			joint = j.branch
+		case r > first && r < last:
+			joint = j.pipe
+		default:
+			joint = " "
+		}
+		tail := "  "
+		if anchors[r] {
+			tail = j.dash + " "
+		}
+		lines[r] = prefix + p.style.guide(joint+tail) + row
+	}
+	return hBlock{lines: lines, anchor: anchor}
+}
@@ -0,0 +1,35 @@
+package treego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+)
+
+// stableIDLen is the number of hex characters kept from the SHA-256 digest.
+// 64 bits keeps IDs short while making collisions negligible for real trees.
+const stableIDLen = 16
+
+// StableID derives a deterministic ID from a path relative to the scan root.
+// Separators are normalized so the same entry gets the same ID on every OS.
+func StableID(path string) string {
+	sum := sha256.Sum256([]byte(filepath.ToSlash(filepath.Clean(path))))
+	return hex.EncodeToString(sum[:])[:stableIDLen]
+}
+
+// AssignStableIDs sets the ID of root and all of its descendants from their
+// path relative to root. The root itself is identified by ".".
+func AssignStableIDs(root *Node) {
+	assignStableIDs(root, ".")
+}
+
+func assignStableIDs(node *Node, rel string) {
+	node.ID = StableID(rel)
+	for _, child := range node.Children {
+		childRel := child.Name
+		if rel != "." {
+			childRel = rel + "/" + child.Name
+		}
+		assignStableIDs(child, childRel)
+	}
+}
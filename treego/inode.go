@@ -0,0 +1,67 @@
+package treego
+
+import (
+	"io"
+	"os"
+)
+
+// inodeKey identifies a filesystem object across hard links.
+type inodeKey struct {
+	dev, ino uint64
+}
+
+// InodeSummary counts the inodes a tree consumes. Hard-linked files share
+// one inode and are counted once; Bytes likewise counts their size once.
+type InodeSummary struct {
+	Dirs      int
+	Files     int
+	Inodes    int
+	HardLinks int // file entries that share an inode with an earlier one
+	Bytes     int64
+}
+
+// CountInodes stats every entry under root, counting each distinct inode
+// once. Entries that cannot be stat'ed (or platforms without inode numbers)
+// count as one inode each.
+func CountInodes(root *Node) InodeSummary {
+	var s InodeSummary
+	seen := make(map[inodeKey]bool)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n.IsDir {
+			s.Dirs++
+		} else {
+			s.Files++
+		}
+		shared := false
+		if info, err := os.Lstat(n.Path); err == nil {
+			if key, ok := fileID(info); ok {
+				shared = seen[key]
+				seen[key] = true
+			}
+		}
+		if shared {
+			s.HardLinks++
+		} else {
+			s.Inodes++
+			s.Bytes += n.Size
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return s
+}
+
+// PrintInodeSummary writes the byte and inode totals of s.
+func PrintInodeSummary(w io.Writer, s InodeSummary, opts Options) error {
+	ew := &errWriter{w: w}
+	ew.printf("Size:   %s in %d files\n", HumanizeSize(s.Bytes, opts.sizeBase()), s.Files)
+	ew.printf("Inodes: %d (%d directories, %d files", s.Inodes, s.Dirs, s.Files)
+	if s.HardLinks > 0 {
+		ew.printf("; %d hard links counted once", s.HardLinks)
+	}
+	ew.printf(")\n")
+	return ew.err
+}
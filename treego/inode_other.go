@@ -0,0 +1,11 @@
+//go:build !unix
+
+package treego
+
+import "os"
+
+// fileID is unavailable on this platform, so every entry counts as its
+// own inode.
+func fileID(info os.FileInfo) (inodeKey, bool) {
+	return inodeKey{}, false
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package treego
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the device and inode number behind info.
+func fileID(info os.FileInfo) (inodeKey, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return inodeKey{}, false
+	}
+	return inodeKey{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
@@ -0,0 +1,85 @@
+package treego
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// IOProfile tunes how many directories a scan reads concurrently for the
+// kind of storage being scanned.
+type IOProfile int
+
+const (
+	// IOAuto detects the profile from the filesystem holding the scanned
+	// path (see DetectIOProfile).
+	IOAuto IOProfile = iota
+	// IOSSD uses wide parallelism, which solid-state storage serves well.
+	IOSSD
+	// IOHDD keeps few reads in flight so a spinning disk is not thrashed
+	// by seeks between directories.
+	IOHDD
+	// IONetwork uses moderate parallelism: enough to overlap round trips
+	// to a network filesystem without flooding the server.
+	IONetwork
+)
+
+// ParseIOProfile parses "auto", "ssd", "hdd" or "network".
+func ParseIOProfile(s string) (IOProfile, error) {
+	switch s {
+	case "", "auto":
+		return IOAuto, nil
+	case "ssd":
+		return IOSSD, nil
+	case "hdd":
+		return IOHDD, nil
+	case "network":
+		return IONetwork, nil
+	default:
+		return IOAuto, fmt.Errorf("invalid io profile %q (want auto, ssd, hdd or network)", s)
+	}
+}
+
+// String returns the name accepted by ParseIOProfile.
+func (p IOProfile) String() string {
+	switch p {
+	case IOSSD:
+		return "ssd"
+	case IOHDD:
+		return "hdd"
+	case IONetwork:
+		return "network"
+	default:
+		return "auto"
+	}
+}
+
+// DetectIOProfile guesses the profile for the filesystem holding path:
+// IONetwork for NFS, SMB and similar mounts, IOHDD for rotational disks
+// (Linux only) and IOSSD otherwise, including when detection fails.
+func DetectIOProfile(path string) IOProfile {
+	if p := detectIOProfile(path); p != IOAuto {
+		return p
+	}
+	return IOSSD
+}
+
+// Workers returns the number of directories read concurrently under p.
+// IOAuto is treated as IOSSD; resolve it with DetectIOProfile first.
+func (p IOProfile) Workers() int {
+	switch p {
+	case IOHDD:
+		return 4
+	case IONetwork:
+		return 16
+	}
+	// Bound the reads in flight so huge trees don't open thousands of
+	// directories at once, while still keeping fast storage busy.
+	n := runtime.GOMAXPROCS(0) * 16
+	if n < 32 {
+		n = 32
+	}
+	if n > 512 {
+		n = 512
+	}
+	return n
+}
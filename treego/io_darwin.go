@@ -0,0 +1,22 @@
+package treego
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// networkFilesystems are the statfs type names of network filesystems.
+var networkFilesystems = map[string]bool{
+	"nfs": true, "smbfs": true, "afpfs": true, "webdav": true, "cifs": true,
+}
+
+// detectIOProfile checks the filesystem type name reported by statfs.
+func detectIOProfile(path string) IOProfile {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return IOAuto
+	}
+	if networkFilesystems[unix.ByteSliceToString(fs.Fstypename[:])] {
+		return IONetwork
+	}
+	return IOAuto
+}
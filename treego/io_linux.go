@@ -0,0 +1,59 @@
+package treego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// networkFilesystems are the statfs magic numbers of network filesystems.
+var networkFilesystems = map[int64]bool{
+	unix.NFS_SUPER_MAGIC:  true,
+	unix.SMB_SUPER_MAGIC:  true,
+	unix.SMB2_SUPER_MAGIC: true,
+	unix.CIFS_SUPER_MAGIC: true,
+	unix.AFS_SUPER_MAGIC:  true,
+	unix.CODA_SUPER_MAGIC: true,
+	unix.CEPH_SUPER_MAGIC: true,
+}
+
+// detectIOProfile checks the filesystem type with statfs, then whether
+// the backing block device is rotational according to sysfs.
+func detectIOProfile(path string) IOProfile {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return IOAuto
+	}
+	if networkFilesystems[int64(fs.Type)] {
+		return IONetwork
+	}
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return IOAuto
+	}
+	// /sys/dev/block/M:m links into /sys/devices; resolve it before going
+	// up, since filepath.Join would clean ".." away lexically.
+	dev, err := filepath.EvalSymlinks(fmt.Sprintf("/sys/dev/block/%d:%d", unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev))))
+	if err != nil {
+		return IOAuto
+	}
+	return rotationalProfile(dev)
+}
+
+// rotationalProfile reads queue/rotational for the block device at the
+// resolved sysfs directory dev. Partitions keep their queue settings on
+// the parent disk, one level up.
+func rotationalProfile(dev string) IOProfile {
+	for _, q := range []string{filepath.Join(dev, "queue", "rotational"), filepath.Join(filepath.Dir(dev), "queue", "rotational")} {
+		if b, err := os.ReadFile(q); err == nil {
+			if strings.TrimSpace(string(b)) == "1" {
+				return IOHDD
+			}
+			return IOSSD
+		}
+	}
+	return IOAuto
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package treego
+
+// detectIOProfile has no way to inspect the filesystem on this platform.
+func detectIOProfile(path string) IOProfile {
+	return IOAuto
+}
@@ -0,0 +1,87 @@
+package treego
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrOutputLimit is returned by a LimitWriter once its budget is spent.
+var ErrOutputLimit = errors.New("output limit reached")
+
+// LimitWriter passes at most a fixed number of bytes through to the
+// underlying writer. Output is passed on a line at a time: an unfinished
+// last line is held back until its newline arrives, and is dropped if the
+// limit is reached first, so output never ends mid-line. The write that
+// crosses the limit is cut back to its last complete line that fits, and
+// every write from then on fails with ErrOutputLimit, which stops the
+// renderers. Call Flush once done to write a final line that has no
+// newline.
+type LimitWriter struct {
+	w         io.Writer
+	remaining int64
+	partial   []byte // the unfinished last line, not yet written
+	exceeded  bool
+}
+
+// NewLimitWriter returns a writer that lets n bytes through to w.
+func NewLimitWriter(w io.Writer, n int64) *LimitWriter {
+	return &LimitWriter{w: w, remaining: n}
+}
+
+func (l *LimitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		l.exceeded = true
+		fit := bytes.LastIndexByte(p[:l.remaining], '\n') + 1
+		l.remaining = 0
+		if fit == 0 {
+			l.partial = nil
+			return 0, ErrOutputLimit
+		}
+		n, err := l.emit(p[:fit])
+		l.partial = nil
+		if err == nil {
+			err = ErrOutputLimit
+		}
+		return n, err
+	}
+	l.remaining -= int64(len(p))
+	end := bytes.LastIndexByte(p, '\n') + 1
+	if end == 0 {
+		l.partial = append(l.partial, p...)
+		return len(p), nil
+	}
+	if n, err := l.emit(p[:end]); err != nil {
+		return n, err
+	}
+	l.partial = append(l.partial, p[end:]...)
+	return len(p), nil
+}
+
+// emit writes the held-back partial line followed by p, which ends in a
+// newline, and returns how much of p was written.
+func (l *LimitWriter) emit(p []byte) (int, error) {
+	buf := append(l.partial, p...)
+	l.partial = l.partial[:0]
+	n, err := l.w.Write(buf)
+	n -= len(buf) - len(p)
+	return max(n, 0), err
+}
+
+// Flush writes the held-back last line, for output that does not end in a
+// newline. It does nothing once the limit has been reached.
+func (l *LimitWriter) Flush() error {
+	if len(l.partial) == 0 {
+		return nil
+	}
+	_, err := l.w.Write(l.partial)
+	l.partial = l.partial[:0]
+	return err
+}
+
+// Exceeded reports whether any output was dropped.
+func (l *LimitWriter) Exceeded() bool { return l.exceeded }
+
+// Unwrap returns the underlying writer, so terminal detection sees
+// through the limit.
+func (l *LimitWriter) Unwrap() io.Writer { return l.w }
@@ -0,0 +1,107 @@
+package treego
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// signature is one entry of the magic-byte table: a file starts with
+// magic at the given offset.
+type signature struct {
+	name   string
+	offset int
+	magic  []byte
+}
+
+// signatures lists the recognized formats. More specific entries come
+// first where prefixes overlap.
+var signatures = []signature{
+	{"PNG", 0, []byte("\x89PNG\r\n\x1a\n")},
+	{"JPEG", 0, []byte{0xff, 0xd8, 0xff}},
+	{"GIF", 0, []byte("GIF8")},
+	{"WEBP", 8, []byte("WEBP")},
+	{"BMP", 0, []byte("BM")},
+	{"ICO", 0, []byte{0x00, 0x00, 0x01, 0x00}},
+	{"PDF", 0, []byte("%PDF-")},
+	{"ZIP", 0, []byte("PK\x03\x04")},
+	{"ZIP", 0, []byte("PK\x05\x06")},
+	{"GZIP", 0, []byte{0x1f, 0x8b}},
+	{"BZIP2", 0, []byte("BZh")},
+	{"XZ", 0, []byte("\xfd7zXZ\x00")},
+	{"ZSTD", 0, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{"7Z", 0, []byte("7z\xbc\xaf\x27\x1c")},
+	{"RAR", 0, []byte("Rar!\x1a\x07")},
+	{"TAR", 257, []byte("ustar")},
+	{"ELF", 0, []byte("\x7fELF")},
+	{"Mach-O", 0, []byte{0xcf, 0xfa, 0xed, 0xfe}},
+	{"Mach-O", 0, []byte{0xce, 0xfa, 0xed, 0xfe}},
+	{"Mach-O", 0, []byte{0xca, 0xfe, 0xba, 0xbe}},
+	{"PE", 0, []byte("MZ")},
+	{"WASM", 0, []byte("\x00asm")},
+	{"SQLite", 0, []byte("SQLite format 3\x00")},
+	{"WAV", 8, []byte("WAVE")},
+	{"MP3", 0, []byte("ID3")},
+	{"OGG", 0, []byte("OggS")},
+	{"FLAC", 0, []byte("fLaC")},
+	{"MP4", 4, []byte("ftyp")},
+	{"WOFF", 0, []byte("wOFF")},
+	{"WOFF2", 0, []byte("wOF2")},
+}
+
+// signatureLen is how many leading bytes DetectSignature reads.
+var signatureLen = func() int {
+	n := 0
+	for _, s := range signatures {
+		if end := s.offset + len(s.magic); end > n {
+			n = end
+		}
+	}
+	return n
+}()
+
+// MatchSignature returns the format whose magic bytes head starts with, or
+// "" when none is recognized.
+func MatchSignature(head []byte) string {
+	for _, s := range signatures {
+		if len(head) >= s.offset+len(s.magic) && bytes.Equal(head[s.offset:s.offset+len(s.magic)], s.magic) {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// DetectSignature reads the first few bytes of the file at path and
+// returns its recognized format, or "" if it is unknown or unreadable.
+func DetectSignature(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	head := make([]byte, signatureLen)
+	n, _ := io.ReadFull(f, head)
+	return MatchSignature(head[:n])
+}
+
+// DetectSignatures sets Signature on every regular file under root,
+// reading the files concurrently. Files marked SkipContent are not read.
+func DetectSignatures(root *Node) {
+	var files []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			if n.Mode.IsRegular() && !n.SkipContent {
+				files = append(files, n)
+			}
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	parallelEach(len(files), func(i int) {
+		files[i].Signature = DetectSignature(files[i].Path)
+	})
+}
@@ -1,21 +1,75 @@
 package treego
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
-	"runtime"
-	"sort"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Node struct {
-	Name     string
-	Children []*Node
-	IsDir    bool
-	Path     string
+	ID      string    `json:"id,omitempty"`
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size,omitempty"` // files only
+	ModTime time.Time `json:"modTime"`
+	// Mode holds the type and permission bits from the entry's lstat (stat
+	// for the root and followed links).
+	Mode os.FileMode `json:"mode,omitempty"`
+	// LinkTarget is the target stored in a symlink that was not followed.
+	LinkTarget string `json:"linkTarget,omitempty"`
+	// Truncated marks a directory whose listing was cut short, e.g. by a
+	// scan timeout; its Children are incomplete.
+	Truncated bool `json:"truncated,omitempty"`
+	// LinkError explains why a followed symlink was not descended into,
+	// e.g. "too many links" when its chain exceeds the depth limit.
+	LinkError string `json:"linkError,omitempty"`
+	// Owner is recorded when Options.ShowOwner or RecordOwner is set (Unix
+	// only).
+	Owner *FileOwner `json:"owner,omitempty"`
+	// AccessTime and ChangeTime (the inode status change time) are recorded
+	// when Options.ShowATime or ShowCTime is set, on platforms that expose
+	// them.
+	AccessTime *time.Time `json:"accessTime,omitempty"`
+	ChangeTime *time.Time `json:"changeTime,omitempty"`
+	// LastCommit is the most recent commit touching a tracked file, set
+	// by AnnotateGitAuthors.
+	LastCommit *GitCommit `json:"lastCommit,omitempty"`
+	// Hash is the hex SHA-256 digest of a file's content, set by
+	// HashFiles.
+	Hash string `json:"sha256,omitempty"`
+	// Signature is the file format recognized from the file's leading
+	// bytes, such as "PNG" or "ELF", set by DetectSignatures.
+	Signature string `json:"signature,omitempty"`
+	// SameAs is the path, relative to the root, of an identical directory
+	// printed earlier; set by FoldIdentical, which drops the children.
+	SameAs string `json:"sameAs,omitempty"`
+	// Readme holds the first lines of a directory's README, set by
+	// AttachReadmePreviews.
+	Readme []string `json:"readme,omitempty"`
+	// Symbols lists a source file's top-level declarations, set by
+	// ExtractSymbols.
+	Symbols []Symbol `json:"symbols,omitempty"`
+	// Preview holds the first lines of a text file, set by
+	// AttachPreviews.
+	Preview []string `json:"preview,omitempty"`
+	// SkipContent marks a file the content-reading features leave
+	// unread, set by LimitContent.
+	SkipContent bool `json:"-"`
+	// Rank is a file's position by modification time across the tree (1 =
+	// most recent), set by RankRecent.
+	Rank int `json:"rank,omitempty"`
+	// Totals holds a directory's recursive counts, set by AggregateAll.
+	Totals   *DirTotals `json:"totals,omitempty"`
+	Children []*Node    `json:"children,omitempty"`
 }
 
 type job struct {
@@ -115,20 +169,163 @@ func BuildTreeSafe(path string) *Node {
 }
 
 func BuildTreeSafeWithExcludes(path string, excludes []ExcludeMatcher) *Node {
-	// Bound parallelism to avoid creating one goroutine per file/dir entry.
-	// This keeps traversal fast on large trees while preventing runaway goroutine/memory usage.
-	maxParallel := runtime.GOMAXPROCS(0) * 16
-	if maxParallel < 32 {
-		maxParallel = 32
+	return BuildFilteredTree(path, Options{Excludes: excludes})
+}
+
+// BuildFilteredTree builds the tree rooted at path, applying the build-time
+// filters in opts (excludes and extensions) during traversal so filtered
+// entries never allocate a Node.
+func BuildFilteredTree(path string, opts Options) *Node {
+	root, _ := BuildTreeContext(context.Background(), path, opts)
+	return root
+}
+
+// BuildTreeContext is BuildFilteredTree with cancellation. When ctx is done
+// (for example after a timeout) traversal stops and whatever was built so
+// far is returned; directories that were not fully read are marked
+// Truncated and partial is true.
+func BuildTreeContext(ctx context.Context, path string, opts Options) (root *Node, partial bool) {
+	b := newBuilder(ctx, opts)
+	root = b.run(path)
+	return root, b.partial.Load()
+}
+
+func newBuilder(ctx context.Context, opts Options) *builder {
+	return &builder{
+		opts:     opts,
+		excludes: opts.Excludes,
+		exts:     normalizeExtensions(opts.Extensions),
+		ctx:      ctx,
+	}
+}
+
+// run builds the tree rooted at path.
+func (b *builder) run(path string) *Node {
+	b.sem = make(chan struct{}, b.opts.workers(path))
+	b.root = path
+	root := b.build(path)
+	if root != nil && !root.IsDir {
+		// A file root has no parent directory to report it.
+		b.files.Add(1)
+		b.bytes.Add(root.Size)
+		if b.opts.OnFile != nil {
+			b.opts.OnFile(root)
+		}
+	}
+	return root
+}
+
+// builder carries the per-build state shared by all traversal goroutines.
+type builder struct {
+	opts     Options
+	excludes []ExcludeMatcher
+	exts     []string
+	sem      chan struct{}
+	ctx      context.Context
+	partial  atomic.Bool
+	hookMu   sync.Mutex
+
+	errMu sync.Mutex
+	errs  []error
+
+	// linkDirs holds the real paths of directories entered through a
+	// followed symlink.
+	linkDirs sync.Map
+
+	// root is the path the build started from. stream, when set, is
+	// called by the root directory once its listing is read, with its
+	// entries in their final order (directories still being built are
+	// placeholders); wait blocks until such an entry is built and returns
+	// it, or nil when it was dropped.
+	root   string
+	stream func(root *Node, entries []*Node, wait func(*Node) *Node)
+
+	// Entry totals, accumulated as directories complete.
+	dirs, files, bytes atomic.Int64
+}
+
+// fail records err. The entry that caused it is left out of the tree and
+// the rest of the build carries on.
+func (b *builder) fail(err error) {
+	b.errMu.Lock()
+	b.errs = append(b.errs, err)
+	b.errMu.Unlock()
+}
+
+// truncated marks a directory node as cut short by cancellation.
+func (b *builder) truncated(node *Node) *Node {
+	node.Truncated = true
+	b.partial.Store(true)
+	return node
+}
+
+// record copies the metadata in info onto node: the mode and symlink
+// target always, owners and times when the options request them.
+func (b *builder) record(node *Node, info os.FileInfo) {
+	node.Mode = info.Mode()
+	if info.Mode()&os.ModeSymlink != 0 {
+		node.LinkTarget, _ = os.Readlink(node.Path)
+	}
+	if b.opts.ShowOwner || b.opts.RecordOwner {
+		node.Owner = fileOwner(info)
+	}
+	if b.opts.ShowATime || b.opts.ShowCTime {
+		if atime, ctime, ok := fileTimes(info); ok {
+			node.AccessTime, node.ChangeTime = &atime, &ctime
+		}
+	}
+}
+
+// keepFile reports whether a file passes the extension filter.
+func (b *builder) keepFile(name string) bool {
+	if len(b.exts) == 0 {
+		return true
 	}
-	if maxParallel > 512 {
-		maxParallel = 512
+	lower := strings.ToLower(name)
+	for _, ext := range b.exts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
 	}
-	sem := make(chan struct{}, maxParallel)
-	return buildTreeSafe(path, excludes, sem)
+	return false
 }
 
-func buildTreeSafe(path string, excludes []ExcludeMatcher, sem chan struct{}) *Node {
+// IsHidden reports whether name is a dot-entry such as ".git" or ".env".
+// The special names "." and ".." are not hidden.
+func IsHidden(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// skipHidden reports whether an entry is dropped by the hidden filters.
+func (b *builder) skipHidden(name string, isDir bool) bool {
+	if !IsHidden(name) {
+		return false
+	}
+	if isDir {
+		return b.opts.NoHiddenDirs
+	}
+	return b.opts.NoHiddenFiles
+}
+
+// normalizeExtensions lower-cases extensions and gives them a leading dot,
+// so "go", ".go" and "GO" are equivalent. Multi-part extensions such as
+// "tar.gz" are supported.
+func normalizeExtensions(exts []string) []string {
+	var out []string
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" || ext == "." {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		out = append(out, ext)
+	}
+	return out
+}
+
+func (b *builder) build(path string) *Node {
 	select {
 	case <-abort:
 		// someone already triggered abort, stop immediately
@@ -138,30 +335,57 @@ func buildTreeSafe(path string, excludes []ExcludeMatcher, sem chan struct{}) *N
 
 	info, err := os.Stat(path)
 	if err != nil {
-		CloseOnce()
+		b.fail(err)
 		return nil
 	}
 
-	if shouldExclude(excludes, info.Name(), path) {
+	if shouldExclude(b.excludes, info.Name(), path) {
 		return nil
 	}
 
-	node := &Node{Name: info.Name(), IsDir: info.IsDir(), Path: path}
+	node := &Node{Name: info.Name(), IsDir: info.IsDir(), Path: path, ModTime: info.ModTime()}
+	b.record(node, info)
 	if !info.IsDir() {
+		node.Size = info.Size()
+		return node
+	}
+	if b.ctx.Err() != nil {
+		b.visit(b.truncated(node))
 		return node
 	}
 
+	// The slot bounds the directory reads, not the goroutines: a directory
+	// waiting on its subdirectories must not hold a slot they need.
+	select {
+	case b.sem <- struct{}{}:
+	case <-abort:
+		return nil
+	case <-b.ctx.Done():
+		b.visit(b.truncated(node))
+		return node
+	}
 	entries, err := os.ReadDir(path)
+	<-b.sem
 	if err != nil {
-		CloseOnce()
+		b.fail(err)
 		return nil
 	}
 
 	// Fast path: process files inline; process directories with bounded parallelism.
 	var (
-		wg    sync.WaitGroup
-		mu    sync.Mutex
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	// When streaming, order collects the root's entries as they are
+	// queued, with a placeholder for each directory in pending.
+	streaming := b.stream != nil && path == b.root
+	var (
+		order   []*Node
+		pending map[*Node]*pendingDir
 	)
+	if streaming {
+		pending = make(map[*Node]*pendingDir)
+	}
 
 	for _, e := range entries {
 		select {
@@ -169,35 +393,91 @@ func buildTreeSafe(path string, excludes []ExcludeMatcher, sem chan struct{}) *N
 			return nil
 		default:
 		}
+		if b.ctx.Err() != nil {
+			mu.Lock()
+			b.truncated(node)
+			mu.Unlock()
+			break
+		}
 
 		name := e.Name()
 		childPath := filepath.Join(path, name)
-		if shouldExclude(excludes, name, childPath) {
+		if shouldExclude(b.excludes, name, childPath) {
 			continue
 		}
 
 		isDir := e.IsDir()
+		var linkNote string
+		followed := b.opts.FollowSymlinks && e.Type()&os.ModeSymlink != 0
+		if followed {
+			isDir, linkNote = b.followLink(childPath)
+		}
+		if b.skipHidden(name, isDir) {
+			continue
+		}
 		if !isDir {
-			// Avoid extra syscalls: trust DirEntry for non-dirs.
+			if !b.keepFile(name) {
+				continue
+			}
+			// Trust DirEntry for the type; Info is a single lstat for size and mtime.
+			child := &Node{Name: name, IsDir: false, Path: childPath, LinkError: linkNote}
+			info, err := e.Info()
+			if followed && linkNote == "" {
+				// A dangling link keeps its own lstat info.
+				if target, terr := os.Stat(childPath); terr == nil {
+					info, err = target, nil
+				}
+			}
+			if err == nil {
+				child.Size = info.Size()
+				child.ModTime = info.ModTime()
+				b.record(child, info)
+			}
 			mu.Lock()
-			node.Children = append(node.Children, &Node{Name: name, IsDir: false, Path: childPath})
+			node.Children = append(node.Children, child)
+			mu.Unlock()
+			if streaming {
+				order = append(order, child)
+			}
+			continue
+		}
+		if linkNote != "" {
+			child := &Node{Name: name, IsDir: true, Path: childPath, LinkError: linkNote}
+			b.visit(child)
+			mu.Lock()
+			node.Children = append(node.Children, child)
 			mu.Unlock()
+			if streaming {
+				order = append(order, child)
+			}
 			continue
 		}
 
+		var pd *pendingDir
+		if streaming {
+			// The placeholder carries the keys SortNodes uses for
+			// directories, so it sorts where the built node will.
+			ph := &Node{Name: name, IsDir: true, Path: childPath}
+			info, err := e.Info()
+			if followed {
+				info, err = os.Stat(childPath)
+			}
+			if err == nil {
+				ph.ModTime = info.ModTime()
+			}
+			pd = &pendingDir{done: make(chan struct{})}
+			pending[ph] = pd
+			order = append(order, ph)
+		}
+
 		wg.Add(1)
 		go func(childPath string) {
 			defer wg.Done()
-
-			// Acquire a slot to bound concurrency.
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-abort:
-				return
+			child := b.build(childPath)
+			if pd != nil {
+				pd.node = child
+				close(pd.done)
 			}
-
-			child := buildTreeSafe(childPath, excludes, sem)
 			if child == nil {
 				return
 			}
@@ -207,33 +487,86 @@ func buildTreeSafe(path string, excludes []ExcludeMatcher, sem chan struct{}) *N
 		}(childPath)
 	}
 
+	if streaming {
+		SortNodes(order, b.opts)
+		b.stream(node, order, func(n *Node) *Node {
+			pd, ok := pending[n]
+			if !ok {
+				return n
+			}
+			<-pd.done
+			return pd.node
+		})
+	}
+
 	wg.Wait()
 
 	// Stable ordering improves UX and makes output deterministic:
 	// directories first, then files; both sorted by name.
-	sort.Slice(node.Children, func(i, j int) bool {
-		a, b := node.Children[i], node.Children[j]
-		if a.IsDir != b.IsDir {
-			return a.IsDir && !b.IsDir
+	SortNodes(node.Children, b.opts)
+	b.visit(node)
+	if b.opts.SummaryOnly {
+		// Counted and reported; only the directory structure is kept.
+		dirs := node.Children[:0]
+		for _, c := range node.Children {
+			if c.IsDir {
+				dirs = append(dirs, c)
+			}
 		}
-		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
-	})
+		clear(node.Children[len(dirs):])
+		node.Children = dirs
+	}
 
 	return node
 }
 
+// pendingDir is a streamed directory that is still being built; done is
+// closed once node is set.
+type pendingDir struct {
+	done chan struct{}
+	node *Node
+}
+
+// visit counts a directory's entries and runs the traversal hooks once its
+// listing is merged: OnFile for each file child in sorted order, then OnDir for the
+// directory itself. Calls are serialized by hookMu, so hooks never run
+// concurrently even though directories are scanned in parallel.
+func (b *builder) visit(dir *Node) {
+	b.dirs.Add(1)
+	for _, c := range dir.Children {
+		if !c.IsDir {
+			b.files.Add(1)
+			b.bytes.Add(c.Size)
+		}
+	}
+	if b.opts.OnFile == nil && b.opts.OnDir == nil {
+		return
+	}
+	b.hookMu.Lock()
+	defer b.hookMu.Unlock()
+	if b.opts.OnFile != nil {
+		for _, c := range dir.Children {
+			if !c.IsDir {
+				b.opts.OnFile(c)
+			}
+		}
+	}
+	if b.opts.OnDir != nil {
+		b.opts.OnDir(dir)
+	}
+}
+
 // helper to close abort channel only once
 var once sync.Once
+
 func CloseOnce() {
 	once.Do(func() {
 		close(abort)
 	})
 }
 
-
-
 func SearchDFS(node *Node, query string) {
-	if strings.Contains(strings.ToLower(node.Name), strings.ToLower(query)) {
+	if nameContains(node.Name, query) {
 		fmt.Println(node.Path)
 	}
 	for _, child := range node.Children {
@@ -242,8 +575,256 @@ func SearchDFS(node *Node, query string) {
 }
 
 func PrintTreeDFS(node *Node, prefix string, relPrefix string, matcher NameMatcher, dirsOnly bool) {
-	for i, child := range node.Children {
-		if dirsOnly && !child.IsDir {
+	p := newTreePrinter(os.Stdout, Options{Matcher: matcher, DirsOnly: dirsOnly, Color: ColorNever})
+	p.print(node, prefix, relPrefix)
+}
+
+// treePrinter holds the settings shared by every level of a tree print.
+type treePrinter struct {
+	w     io.Writer
+	opts  Options
+	style style
+	// heatMax is the largest file size when the heatmap is drawn, else 0.
+	heatMax int64
+	guides  guideSet
+	// lines counts the lines written, for Options.Zebra.
+	lines int
+	// total sums the sizes of the files printed so far, for
+	// Options.RunningTotal.
+	total int64
+	// rows buffers the lines when Options.Columns is set, so the columns
+	// can be aligned once every line is known (see flushTable).
+	rows []tableRow
+}
+
+// guideSet holds the strings that draw the tree structure.
+type guideSet struct {
+	branch, last, pipe, rule string
+	quote                    string // marks file preview lines
+}
+
+var (
+	unicodeGuides = guideSet{branch: "├── ", last: "└── ", pipe: "│   ", rule: "──", quote: "┆ "}
+	asciiGuides   = guideSet{branch: "|-- ", last: "`-- ", pipe: "|   ", rule: "--", quote: "> "}
+)
+
+func newTreePrinter(w io.Writer, opts Options) *treePrinter {
+	p := &treePrinter{w: w, opts: opts, style: newStyle(w, opts), guides: unicodeGuides}
+	if opts.ASCII {
+		p.guides = asciiGuides
+	}
+	return p
+}
+
+// line writes one line of the tree that shows no entry.
+func (p *treePrinter) line(s string) error {
+	return p.row(nil, s)
+}
+
+// row writes the line s showing node, or buffers it for alignment when
+// columns are selected.
+func (p *treePrinter) row(node *Node, s string) error {
+	if len(p.opts.Columns) > 0 {
+		p.rows = append(p.rows, tableRow{node: node, text: s})
+		return nil
+	}
+	return p.write(s)
+}
+
+// write outputs one line, cut to opts.MaxWidth cells if set and shaded
+// when it is an odd line of a zebra print.
+func (p *treePrinter) write(s string) error {
+	if p.opts.MaxWidth > 0 {
+		s = TruncateWidth(s, p.opts.MaxWidth)
+	}
+	if p.opts.Zebra && p.style.color && p.lines%2 == 1 {
+		// Resets inside the line end the name colors; restore the
+		// background after each so the stripe runs to the end of the line.
+		s = ansiZebra + strings.ReplaceAll(s, ansiReset, ansiReset+ansiZebra) + ansiClearLine + ansiReset
+	}
+	p.lines++
+	_, err := fmt.Fprintln(p.w, s)
+	return err
+}
+
+// label returns the text printed for child after the tree guides.
+func (p *treePrinter) label(child *Node) string {
+	name := child.Name
+	if p.heatMax > 0 && !child.IsDir {
+		name = heatColor(heatFraction(child.Size, p.heatMax)) + name + ansiReset
+	}
+	label := p.style.name(child, name)
+	if child.Truncated {
+		label += " [truncated]"
+	}
+	if child.LinkError != "" {
+		label += " [" + child.LinkError + "]"
+	}
+	if child.SameAs != "" {
+		same := "≡"
+		if p.opts.ASCII {
+			same = "="
+		}
+		label += " " + p.style.guide(same+" same as "+child.SameAs)
+	}
+	if len(p.opts.Columns) > 0 {
+		// Sizes, times and owners are in the columns instead.
+		return p.suffixes(child, p.withCounts(child, label))
+	}
+	if p.opts.RunningTotal && !child.IsDir {
+		p.total += child.Size
+		label = "[" + HumanizeSize(child.Size, p.opts.sizeBase()) + ", total " + HumanizeSize(p.total, p.opts.sizeBase()) + "]  " + label
+	} else if p.opts.ShowSize && !child.IsDir {
+		label = "[" + HumanizeSize(child.Size, p.opts.sizeBase()) + "]  " + label
+	}
+	label = p.withTotals(child, label)
+	if p.opts.ShowCTime && child.ChangeTime != nil {
+		label = "[" + p.opts.formatTime(*child.ChangeTime) + "]  " + label
+	}
+	if p.opts.ShowATime && child.AccessTime != nil {
+		label = "[" + p.opts.formatTime(*child.AccessTime) + "]  " + label
+	}
+	if p.opts.ShowOwner && child.Owner != nil {
+		label = "[" + OwnerName(*child.Owner) + "]  " + label
+	}
+	return p.suffixes(child, label)
+}
+
+// suffixes appends the annotations printed after an entry's name.
+func (p *treePrinter) suffixes(child *Node, label string) string {
+	if p.opts.ShowSignature && child.Signature != "" {
+		label += " " + p.style.guide("["+child.Signature+"]")
+	}
+	if p.opts.ShowRank && child.Rank > 0 {
+		label += " " + p.style.guide(fmt.Sprintf("#%d", child.Rank))
+	}
+	if p.opts.ShowGitAuthor && child.LastCommit != nil {
+		label += p.style.guide("  (" + child.LastCommit.Author + ", " + child.LastCommit.Date.Format("2006-01-02") + ")")
+	}
+	return label
+}
+
+// withTotals decorates a directory label with its recursive size and
+// counts when those are requested and node.Totals is set.
+func (p *treePrinter) withTotals(node *Node, label string) string {
+	label = p.withCounts(node, label)
+	if p.opts.ShowDirSize && node.IsDir && node.Totals != nil {
+		label = "[" + HumanizeSize(node.Totals.Bytes, p.opts.sizeBase()) + "]  " + label
+	}
+	return label
+}
+
+// withCounts appends a directory's recursive counts when ShowCounts is set
+// and node.Totals is known.
+func (p *treePrinter) withCounts(node *Node, label string) string {
+	if p.opts.ShowCounts && node.IsDir && node.Totals != nil {
+		t := node.Totals
+		label += p.style.guide(fmt.Sprintf(" (%s, %s)", plural(t.Files, "file"), plural(t.Dirs, "dir")))
+	}
+	return label
+}
+
+// plural formats n with word, adding an s unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// collapseExt keeps the first limit files of each extension and replaces
+// the rest of every group with one summary entry, appended after the other
+// children in order of first appearance. Extensions mapped to a group in
+// groups collapse together under the group name. Files without an
+// extension are never collapsed. Summary entries are returned in the set.
+func collapseExt(children []*Node, limit int, groups map[string]string) ([]*Node, map[*Node]bool) {
+	counts := make(map[string]int)
+	labels := make(map[string]string)
+	var order []string
+	out := make([]*Node, 0, len(children))
+	for _, c := range children {
+		ext := FileType(c.Name, groups)
+		if c.IsDir || ext == "" {
+			out = append(out, c)
+			continue
+		}
+		if _, grouped := groups[strings.ToLower(filepath.Ext(c.Name))[1:]]; grouped {
+			labels[ext] = ext
+		} else if labels[ext] == "" {
+			labels[ext] = "." + ext
+		}
+		if counts[ext] == limit {
+			order = append(order, ext)
+		}
+		if counts[ext]++; counts[ext] <= limit {
+			out = append(out, c)
+		}
+	}
+	if len(order) == 0 {
+		return children, nil
+	}
+	summaries := make(map[*Node]bool, len(order))
+	for _, ext := range order {
+		s := &Node{Name: fmt.Sprintf("... and %d more %s", counts[ext]-limit, labels[ext])}
+		summaries[s] = true
+		out = append(out, s)
+	}
+	return out, summaries
+}
+
+// resolveNext resolves the entries of children from i on until one is
+// kept, removing the dropped ones. Resolving ahead tells whether the entry
+// before i is the last one before it is drawn; a dropped final entry would
+// otherwise leave its predecessor with a branch instead of the last-entry
+// guide.
+func resolveNext(children []*Node, i int, summaries map[*Node]bool, resolve func(*Node) *Node) []*Node {
+	for i < len(children) {
+		if summaries[children[i]] {
+			break
+		}
+		if n := resolve(children[i]); n != nil {
+			children[i] = n
+			break
+		}
+		children = slices.Delete(children, i, i+1)
+	}
+	return children
+}
+
+func (p *treePrinter) print(node *Node, prefix string, relPrefix string) error {
+	return p.printChildren(node.Children, prefix, relPrefix, nil)
+}
+
+// printChildren prints the entries of one directory below prefix. When
+// resolve is set it is called on each entry just before it is printed and
+// returns the node to print in its place, or nil to skip it, so entries
+// can be printed while later ones are still being built.
+func (p *treePrinter) printChildren(children []*Node, prefix string, relPrefix string, resolve func(*Node) *Node) error {
+	matcher := p.opts.Matcher
+	summaries := map[*Node]bool(nil)
+	if p.opts.CollapseExt > 0 && !p.opts.DirsOnly {
+		children, summaries = collapseExt(children, p.opts.CollapseExt, p.opts.ExtGroups)
+	}
+	if resolve != nil {
+		// Entries are resolved in place, one ahead of the entry printed.
+		children = resolveNext(slices.Clone(children), 0, summaries, resolve)
+	}
+	for i := 0; i < len(children); i++ {
+		child := children[i]
+		if resolve != nil {
+			children = resolveNext(children, i+1, summaries, resolve)
+		}
+		if p.opts.DirsOnly && !child.IsDir {
+			continue
+		}
+		if summaries[child] {
+			branch := p.guides.branch
+			if i == len(children)-1 {
+				branch = p.guides.last
+			}
+			if err := p.line(p.style.guide(prefix+branch) + p.style.guide(child.Name)); err != nil {
+				return err
+			}
 			continue
 		}
 		rel := child.Name
@@ -277,18 +858,91 @@ func PrintTreeDFS(node *Node, prefix string, relPrefix string, matcher NameMatch
 				continue
 			}
 		}
-		last := i == len(node.Children)-1
-		branch := "├── "
-		nextPrefix := prefix + "│   "
+		last := i == len(children)-1
+		branch := p.guides.branch
+		nextPrefix := prefix + p.guides.pipe
 		if last {
-			branch = "└── "
+			branch = p.guides.last
 			nextPrefix = prefix + "    "
 		}
-		fmt.Println(prefix + branch + child.Name)
+		depth := strings.Count(rel, "/") + 1
+		if child.IsDir && p.opts.Breadcrumbs > 0 && depth == p.opts.Breadcrumbs {
+			crumb := p.guides.rule + " " + rel + " " + p.guides.rule
+			if err := p.line(p.style.guide(prefix) + p.style.guide(crumb)); err != nil {
+				return err
+			}
+		}
+		if err := p.row(child, p.style.guide(prefix+branch)+p.label(child)); err != nil {
+			return err
+		}
+		expand := p.opts.MaxDepth <= 0 || depth < p.opts.MaxDepth
 		if child.IsDir {
-			PrintTreeDFS(child, nextPrefix, rel, matcher, dirsOnly)
+			if err := p.readme(child, nextPrefix, expand); err != nil {
+				return err
+			}
+		} else if err := p.preview(child, nextPrefix); err != nil {
+			return err
+		}
+		if !expand {
+			continue
+		}
+		if child.IsDir {
+			if err := p.print(child, nextPrefix, rel); err != nil {
+				return err
+			}
+		} else if p.opts.ShowSymbols {
+			if err := p.symbols(child, nextPrefix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readme writes a directory's README preview lines below its name, with
+// the guide to its children carried through them when they are expanded.
+func (p *treePrinter) readme(dir *Node, prefix string, expand bool) error {
+	if !p.opts.ShowReadme {
+		return nil
+	}
+	guide := "    "
+	if expand && len(dir.Children) > 0 {
+		guide = p.guides.pipe
+	}
+	for _, text := range dir.Readme {
+		if err := p.line(p.style.guide(prefix+guide) + p.style.guide(text)); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// preview writes a file's preview lines dimmed below its name, each
+// marked with the quote guide.
+func (p *treePrinter) preview(file *Node, prefix string) error {
+	if !p.opts.ShowPreview {
+		return nil
+	}
+	for _, text := range file.Preview {
+		if err := p.line(p.style.guide(prefix + p.guides.quote + text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// symbols writes a file's symbols as dim entries one level below it.
+func (p *treePrinter) symbols(file *Node, prefix string) error {
+	for i, s := range file.Symbols {
+		branch := p.guides.branch
+		if i == len(file.Symbols)-1 {
+			branch = p.guides.last
+		}
+		if err := p.line(p.style.guide(prefix+branch+s.Kind+" ") + s.Name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ResetGlobalState resets the global abort channel and once variable for testing
@@ -296,4 +950,3 @@ func ResetGlobalState() {
 	abort = make(chan struct{})
 	once = sync.Once{}
 }
-
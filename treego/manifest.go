@@ -0,0 +1,274 @@
+package treego
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HashFile returns the hex-encoded SHA-256 digest of the file at path.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFiles sets Hash on every file under root to its SHA-256 digest,
+// reading files concurrently. Only regular files are read: symlinks and
+// special files, like files that cannot be read or are marked SkipContent,
+// are left without a hash. The first read error is returned after all
+// files are processed.
+func HashFiles(root *Node) error {
+	files := contentFiles(root)
+	errs := make([]error, len(files))
+	parallelEach(len(files), func(i int) {
+		files[i].Node.Hash, errs[i] = HashFile(files[i].Node.Path)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relFile is a file node together with its slash-separated path relative
+// to the scan root.
+type relFile struct {
+	Rel  string
+	Node *Node
+}
+
+// relFiles lists the files under root in tree order.
+func relFiles(root *Node) []relFile {
+	var out []relFile
+	var walk func(n *Node, rel string)
+	walk = func(n *Node, rel string) {
+		if !n.IsDir {
+			out = append(out, relFile{Rel: rel, Node: n})
+			return
+		}
+		for _, c := range n.Children {
+			childRel := c.Name
+			if rel != "" {
+				childRel = rel + "/" + c.Name
+			}
+			walk(c, childRel)
+		}
+	}
+	if root.IsDir {
+		walk(root, "")
+	} else {
+		walk(root, root.Name)
+	}
+	return out
+}
+
+// contentFiles is relFiles limited to the files whose content is hashed:
+// regular files (and followed symlinks to them) not marked SkipContent.
+// Symlinks that were not followed are left out rather than opened, which
+// would hash their target or fail on a directory or dangling link, and so
+// are devices, sockets and FIFOs.
+func contentFiles(root *Node) []relFile {
+	var out []relFile
+	for _, f := range relFiles(root) {
+		if f.Node.Mode&os.ModeType == 0 && !f.Node.SkipContent {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// hashTree hashes every file under root concurrently, leaving out those
+// contentFiles skips. Files that cannot be read map to an empty digest.
+func hashTree(root *Node) map[string]string {
+	files := contentFiles(root)
+	sums := make([]string, len(files))
+	parallelEach(len(files), func(i int) {
+		sums[i], _ = HashFile(files[i].Node.Path)
+	})
+	out := make(map[string]string, len(files))
+	for i, f := range files {
+		out[f.Rel] = sums[i]
+	}
+	return out
+}
+
+// WriteManifest writes a sha256sum-compatible manifest ("<digest>  <path>")
+// for every regular file under node, with paths relative to node. Symlinks
+// that were not followed, special files and files marked SkipContent are
+// left out.
+func WriteManifest(node *Node, w io.Writer) error {
+	files := contentFiles(node)
+	sums := make([]string, len(files))
+	errs := make([]error, len(files))
+	parallelEach(len(files), func(i int) {
+		sums[i], errs[i] = HashFile(files[i].Node.Path)
+	})
+	for i, f := range files {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		if _, err := fmt.Fprintf(w, "%s  %s\n", sums[i], f.Rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadManifest parses a manifest written by WriteManifest (or sha256sum,
+// including its "*path" binary-mode marker) into a map of path to digest.
+func ReadManifest(r io.Reader) (map[string]string, error) {
+	out := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sum, path, ok := strings.Cut(line, " ")
+		path = strings.TrimPrefix(strings.TrimPrefix(path, " "), "*")
+		if !ok || len(sum) != sha256.Size*2 || path == "" {
+			return nil, fmt.Errorf("manifest line %d: malformed entry", n)
+		}
+		out[path] = strings.ToLower(sum)
+	}
+	return out, sc.Err()
+}
+
+// Verification lists the differences between a manifest and a tree.
+// Paths are relative to the scan root and sorted.
+type Verification struct {
+	Missing []string // in the manifest but not in the tree
+	Extra   []string // in the tree but not in the manifest
+	Changed []string // in both, with a different digest
+}
+
+// OK reports whether the tree matches the manifest exactly.
+func (v Verification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Extra) == 0 && len(v.Changed) == 0
+}
+
+// VerifyManifest hashes the files under root and compares them with manifest.
+// Only the files WriteManifest would list are hashed; the others (such as
+// symlinks and files marked SkipContent) are not checked, whether or not
+// the manifest lists them.
+func VerifyManifest(root *Node, manifest map[string]string) Verification {
+	current := hashTree(root)
+	skipped := make(map[string]bool)
+	for _, f := range relFiles(root) {
+		if _, hashed := current[f.Rel]; !hashed {
+			skipped[f.Rel] = true
+		}
+	}
+
+	var v Verification
+	for path, want := range manifest {
+		got, ok := current[path]
+		switch {
+		case skipped[path]:
+			// Not read, so neither missing nor changed.
+		case !ok:
+			v.Missing = append(v.Missing, path)
+		case got != want:
+			v.Changed = append(v.Changed, path)
+		}
+	}
+	for path := range current {
+		if _, ok := manifest[path]; !ok {
+			v.Extra = append(v.Extra, path)
+		}
+	}
+	sort.Strings(v.Missing)
+	sort.Strings(v.Extra)
+	sort.Strings(v.Changed)
+	return v
+}
+
+// PrintVerification writes one line per discrepancy ("- missing",
+// "+ extra", "~ changed") followed by a summary line.
+func PrintVerification(w io.Writer, v Verification) error {
+	ew := &errWriter{w: w}
+	for _, p := range v.Missing {
+		ew.printf("- %s\n", p)
+	}
+	for _, p := range v.Extra {
+		ew.printf("+ %s\n", p)
+	}
+	for _, p := range v.Changed {
+		ew.printf("~ %s\n", p)
+	}
+	if v.OK() {
+		ew.printf("OK: tree matches manifest\n")
+	} else {
+		ew.printf("%d missing, %d extra, %d changed\n", len(v.Missing), len(v.Extra), len(v.Changed))
+	}
+	return ew.err
+}
+
+// TreeHash returns a Merkle-style SHA-256 digest of root: a file hashes to
+// its content digest, and a directory to the digest of its children's
+// types, names and hashes in byte order of name. Two trees with the same
+// structure and contents therefore hash the same regardless of scan order,
+// root name or location. A symlink that was not followed hashes to the
+// target stored in it, without opening it, so links to directories and
+// dangling links hash like any other; other special files hash to their
+// type. Files marked SkipContent are not read and hash to their size
+// instead. Any unreadable regular file is an error.
+func TreeHash(root *Node) (string, error) {
+	files := relFiles(root)
+	sums := make(map[*Node]string, len(files))
+	digests := make([]string, len(files))
+	errs := make([]error, len(files))
+	parallelEach(len(files), func(i int) {
+		switch n := files[i].Node; {
+		case n.Mode&os.ModeSymlink != 0:
+			digests[i] = "link:" + n.LinkTarget
+		case n.Mode&os.ModeType != 0:
+			digests[i] = "type:" + n.Mode.Type().String()
+		case n.SkipContent:
+			digests[i] = "size:" + strconv.FormatInt(n.Size, 10)
+		default:
+			digests[i], errs[i] = HashFile(n.Path)
+		}
+	})
+	for i, f := range files {
+		if errs[i] != nil {
+			return "", errs[i]
+		}
+		sums[f.Node] = digests[i]
+	}
+	return merkleHash(root, sums), nil
+}
+
+func merkleHash(n *Node, sums map[*Node]string) string {
+	if !n.IsDir {
+		return sums[n]
+	}
+	children := append([]*Node(nil), n.Children...)
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	h := sha256.New()
+	for _, c := range children {
+		kind := "f"
+		if c.IsDir {
+			kind = "d"
+		}
+		// NUL cannot occur in file names, so entries cannot run together.
+		fmt.Fprintf(h, "%s %s\x00%s\n", kind, c.Name, merkleHash(c, sums))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,187 @@
+package treego
+
+import "time"
+
+// DefaultTimeFormat is the layout used for printed times when
+// Options.TimeFormat is empty.
+const DefaultTimeFormat = "2006-01-02 15:04"
+
+// Options controls how a tree is built, filtered and rendered.
+// The zero value includes and renders every entry.
+type Options struct {
+	// Excludes drops matching entries (and whole subtrees) during the build.
+	Excludes []ExcludeMatcher
+	// Extensions, when non-empty, keeps only files whose name ends with one
+	// of the listed extensions ("go", ".go" and "tar.gz" are all accepted).
+	// Directories are always traversed. Applied during the build.
+	Extensions []string
+	// NoHiddenDirs skips dot-directories such as .git and .cache, with
+	// everything inside them. The root is never skipped.
+	NoHiddenDirs bool
+	// NoHiddenFiles skips dotfiles such as .env. Applied, like
+	// NoHiddenDirs, during the build.
+	NoHiddenFiles bool
+	// Sort selects the entry order within each directory.
+	Sort SortMode
+	// SortCaseSensitive compares names by raw byte order, so uppercase
+	// sorts before lowercase. By default names compare case-insensitively.
+	SortCaseSensitive bool
+	// Deterministic forces the canonical order: directories first, then
+	// names by raw byte order, overriding Sort and SortCaseSensitive. The
+	// result depends only on the entry names, never on the filesystem,
+	// the locale or the order concurrent reads finish in.
+	Deterministic bool
+	// FollowSymlinks descends into symlinked directories and reports the
+	// size of symlinked files' targets. Each directory is entered through a
+	// link at most once, so link cycles terminate.
+	FollowSymlinks bool
+	// IO selects the concurrency preset for the storage being scanned;
+	// IOAuto (the zero value) detects it from the scanned path.
+	IO IOProfile
+	// Workers, when positive, overrides the number of directories read
+	// concurrently chosen by IO.
+	Workers int
+	// MaxSymlinkDepth caps the hops followed in a chain of symlinks; longer
+	// chains are marked "too many links". Zero means DefaultMaxSymlinkDepth.
+	MaxSymlinkDepth int
+	// SummaryOnly drops file nodes once their directory has been counted,
+	// so memory stays bounded on huge trees when only totals (see Scan)
+	// are needed. Directory nodes are still kept.
+	SummaryOnly bool
+	// OnFile and OnDir, when set, are called for every entry kept by the
+	// build, so embedders can collect metrics without walking the tree
+	// again. Each directory's files are reported in sorted order, followed
+	// by the directory itself once all of its descendants have been
+	// reported; the root directory is always last. Calls never overlap, but
+	// sibling directories may be reported in any order. The node is fully
+	// built when passed and must not be modified.
+	OnFile func(node *Node)
+	OnDir  func(node *Node)
+
+	// Matcher limits output to entries whose name or relative path matches.
+	// Directories are kept when one of their direct children matches.
+	Matcher NameMatcher
+	// DirsOnly hides file entries. The tree printer skips them, and
+	// PrepareTree (so RenderNode, for every format) drops them, leaving
+	// just the directory skeleton.
+	DirsOnly bool
+	// CollapseExt, when positive, prints only the first CollapseExt files of
+	// each extension in a directory, followed by "... and N more .ext".
+	CollapseExt int
+	// ASCII draws the tree guides with |-- and `-- instead of box-drawing
+	// characters.
+	ASCII bool
+	// MaxDepth, when positive, prints entries at most this many levels
+	// below the root. The tree is still built in full.
+	MaxDepth int
+	// MaxWidth, when positive, cuts tree lines longer than this many
+	// terminal cells, ending them with "...".
+	MaxWidth int
+	// Zebra shades every other line of the tree with a subtle background,
+	// leaving foreground colors alone. It needs colors (see Color).
+	Zebra bool
+	// Breadcrumbs, when positive, prints a "── path ──" header with the
+	// directory's path relative to the root before every directory at that
+	// depth (1 for top-level directories), to help orientation in long
+	// output. Tree format only.
+	Breadcrumbs int
+	// ExtGroups maps lower-case extensions (without the dot) to a shared
+	// type name, such as jpg and jpeg to "image", for the composition bar,
+	// SortExt and CollapseExt. See ParseExtGroups.
+	ExtGroups map[string]string
+	// RootLabel replaces the root name in the tree header, e.g. with the
+	// absolute path of the scanned directory. Empty uses the node name.
+	RootLabel string
+	// ShowOwner records each entry's owner during the build and prints it
+	// as "user:group" before the name. Names are resolved once per id.
+	ShowOwner bool
+	// RecordOwner records owners like ShowOwner without printing them, for
+	// filters such as OwnedBy.
+	RecordOwner bool
+	// ShowATime and ShowCTime record each entry's access time and inode
+	// status change time during the build and print them before the name,
+	// access time first. Platforms without these times print nothing.
+	ShowATime bool
+	ShowCTime bool
+	// TimeFormat is the time.Format layout for the times printed by
+	// ShowATime and ShowCTime. Empty uses DefaultTimeFormat.
+	TimeFormat string
+	// ShowSignature prints each file's Signature (see DetectSignatures),
+	// e.g. "[PNG]", after its name.
+	ShowSignature bool
+	// ShowReadme prints each directory's Readme (see
+	// AttachReadmePreviews) indented under its name.
+	ShowReadme bool
+	// ShowPreview prints each file's Preview (see AttachPreviews) dimmed
+	// under its name.
+	ShowPreview bool
+	// ShowSymbols prints each file's Symbols (see ExtractSymbols) nested
+	// under it, like an outline.
+	ShowSymbols bool
+	// ShowRank prints each file's Rank (see RankRecent), e.g. "#3", after
+	// its name.
+	ShowRank bool
+	// ShowGitAuthor prints the author and date of each file's LastCommit
+	// (see AnnotateGitAuthors) after its name.
+	ShowGitAuthor bool
+	// ShowDirSize prints each directory's total size (like du) before its
+	// name, and ShowCounts its recursive file and directory counts after
+	// it. Both read Node.Totals; the tree printer runs AggregateAll when
+	// the root has none yet.
+	ShowDirSize bool
+	ShowCounts  bool
+	// Columns, when set, prints the listed columns (see ParseColumns) as an
+	// aligned table in the given order, with NameColumn holding the tree.
+	// It replaces the size, time and owner annotations of ShowSize,
+	// RunningTotal, ShowDirSize, ShowATime, ShowCTime and ShowOwner; the
+	// data for the owner, atime and ctime columns is still recorded by
+	// those options (or RecordOwner).
+	Columns []string
+	// ShowSize prints each file's size, human formatted, before its name.
+	ShowSize bool
+	// RunningTotal prints, with each file's size, the sum of the sizes of
+	// every file printed so far, as "[4 KiB, total 1.2 MiB]".
+	RunningTotal bool
+	// SI formats sizes with 1000-based units (KB, MB) instead of the default
+	// 1024-based units (KiB, MiB).
+	SI bool
+	// Heatmap colors file names from green (small) to red (largest file in
+	// the tree) and prints a legend. Without colors it shows sizes instead.
+	Heatmap bool
+	// Color selects when colors, dim guides, icons and hyperlinks are used.
+	// Every visual feature is gated by ColorEnabled, so auto mode falls
+	// back to plain output when the writer is not a terminal.
+	Color ColorMode
+	// Icons prefixes entries with a file or folder icon.
+	Icons bool
+	// Hyperlinks wraps entry names in OSC 8 terminal hyperlinks.
+	Hyperlinks bool
+}
+
+// sizeBase returns the unit base selected by SI.
+func (o Options) sizeBase() int64 {
+	if o.SI {
+		return SIBase
+	}
+	return BinaryBase
+}
+
+// formatTime formats t with TimeFormat, or DefaultTimeFormat when unset.
+func (o Options) formatTime(t time.Time) string {
+	if o.TimeFormat == "" {
+		return t.Format(DefaultTimeFormat)
+	}
+	return t.Format(o.TimeFormat)
+}
+
+// workers returns the scan concurrency for a build rooted at path.
+func (o Options) workers(path string) int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	p := o.IO
+	if p == IOAuto {
+		p = DetectIOProfile(path)
+	}
+	return p.Workers()
+}
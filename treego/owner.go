@@ -0,0 +1,77 @@
+package treego
+
+import (
+	"errors"
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+// FileOwner is the numeric owner of a filesystem entry.
+type FileOwner struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+}
+
+// ErrNoOwners is returned by CurrentUID on platforms without Unix file
+// ownership.
+var ErrNoOwners = errors.New("file ownership is not supported on this platform")
+
+// CurrentUID returns the uid of the running process, resolved once.
+var CurrentUID = sync.OnceValues(currentUID)
+
+// OwnedBy keeps the files under root owned by uid, plus the directories
+// leading to them (see PruneTree). Owners must have been recorded during
+// the build (Options.RecordOwner or ShowOwner); files without one never
+// match. It returns nil when nothing matches.
+func OwnedBy(root *Node, uid uint32) *Node {
+	return PruneTree(root, func(n *Node) bool {
+		return !n.IsDir && n.Owner != nil && n.Owner.UID == uid
+	})
+}
+
+// ownerNames resolves uids and gids to names, caching every lookup so each
+// id hits os/user at most once per process.
+type ownerNames struct {
+	mu     sync.Mutex
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+var owners = &ownerNames{users: make(map[uint32]string), groups: make(map[uint32]string)}
+
+// OwnerName formats o as "user:group". Ids without a name are printed as
+// numbers.
+func OwnerName(o FileOwner) string {
+	return owners.user(o.UID) + ":" + owners.group(o.GID)
+}
+
+func (c *ownerNames) user(uid uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(uid), 10)
+	name := id
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+	return name
+}
+
+func (c *ownerNames) group(gid uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name, ok := c.groups[gid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(gid), 10)
+	name := id
+	if g, err := user.LookupGroupId(id); err == nil {
+		name = g.Name
+	}
+	c.groups[gid] = name
+	return name
+}
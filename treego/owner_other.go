@@ -0,0 +1,14 @@
+//go:build !unix
+
+package treego
+
+import "os"
+
+// fileOwner is unavailable on this platform.
+func fileOwner(info os.FileInfo) *FileOwner {
+	return nil
+}
+
+func currentUID() (uint32, error) {
+	return 0, ErrNoOwners
+}
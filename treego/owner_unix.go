@@ -0,0 +1,21 @@
+//go:build unix
+
+package treego
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the owner recorded in info.
+func fileOwner(info os.FileInfo) *FileOwner {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	return &FileOwner{UID: st.Uid, GID: st.Gid}
+}
+
+func currentUID() (uint32, error) {
+	return uint32(os.Getuid()), nil
+}
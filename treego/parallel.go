@@ -0,0 +1,32 @@
+package treego
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelEach calls fn(i) for every i in [0, n) on a bounded pool of
+// goroutines and returns once all calls have finished. Callers write
+// results into index i of a pre-sized slice to keep tree order.
+func parallelEach(n int, fn func(i int)) {
+	workers := runtime.GOMAXPROCS(0) * 2
+	if workers > n {
+		workers = n
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
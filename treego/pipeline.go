@@ -0,0 +1,43 @@
+package treego
+
+import "io"
+
+// PrepareTree runs the post-build stages of the pipeline on a tree from any
+// source (a scan, a JSON file, an archive or a custom builder): it applies
+// opts.Excludes, opts.Extensions, opts.DirsOnly and the hidden filters and
+// sorts every directory with SortNodes. The input is not modified; the
+// returned tree shares no Node values with it. It returns nil when the root
+// itself is excluded.
+func PrepareTree(node *Node, opts Options) *Node {
+	b := &builder{opts: opts, excludes: opts.Excludes, exts: normalizeExtensions(opts.Extensions)}
+	return b.prepare(node, true)
+}
+
+func (b *builder) prepare(node *Node, isRoot bool) *Node {
+	if shouldExclude(b.excludes, node.Name, node.Path) {
+		return nil
+	}
+	if !isRoot && (b.skipHidden(node.Name, node.IsDir) || !node.IsDir && (b.opts.DirsOnly || !b.keepFile(node.Name))) {
+		return nil
+	}
+
+	out := *node
+	out.Children = nil
+	for _, c := range node.Children {
+		if pc := b.prepare(c, false); pc != nil {
+			out.Children = append(out.Children, pc)
+		}
+	}
+	SortNodes(out.Children, b.opts)
+	return &out
+}
+
+// RenderNode runs the full rendering pipeline (filters, sort, format) on an
+// already-built tree, so any tree source shares the CLI's output path.
+func RenderNode(node *Node, w io.Writer, format string, opts Options) error {
+	prepared := PrepareTree(node, opts)
+	if prepared == nil {
+		return nil
+	}
+	return Render(prepared, w, format, opts)
+}
@@ -0,0 +1,84 @@
+package treego
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// excludePresets are named sets of --exclude patterns.
+var excludePresets = map[string][]string{
+	// common is the noise most project views leave out: VCS metadata,
+	// dependency and build output, caches, OS litter and lock files.
+	"common": {
+		".git", ".hg", ".svn",
+		"node_modules", "bower_components", ".venv", "venv",
+		"__pycache__", "*.pyc", ".pytest_cache", ".mypy_cache", ".tox",
+		"dist", "build", "coverage", ".next", ".cache",
+		".DS_Store", "Thumbs.db",
+		"*.lock", "package-lock.json", "pnpm-lock.yaml", "go.sum",
+	},
+}
+
+// ExcludePreset returns a copy of the patterns of the named preset.
+func ExcludePreset(name string) ([]string, bool) {
+	p, ok := excludePresets[name]
+	return append([]string(nil), p...), ok
+}
+
+// ApplyPresetOverrides edits patterns with the lines read from r: each line
+// adds a pattern, a line starting with ! removes one, and blank lines and
+// # comments are ignored.
+func ApplyPresetOverrides(patterns []string, r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if drop, ok := strings.CutPrefix(line, "!"); ok {
+			kept := patterns[:0]
+			for _, p := range patterns {
+				if p != strings.TrimSpace(drop) {
+					kept = append(kept, p)
+				}
+			}
+			patterns = kept
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, sc.Err()
+}
+
+// PresetConfigPath is where the overrides for the named preset live:
+// treego/<name>-ignore under the user configuration directory (for
+// example ~/.config/treego/common-ignore on Linux).
+func PresetConfigPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "treego", name+"-ignore"), nil
+}
+
+// LoadExcludePreset returns the named preset with the user's overrides
+// from PresetConfigPath applied. A missing config file is not an error.
+func LoadExcludePreset(name string) ([]string, error) {
+	patterns, _ := ExcludePreset(name)
+	path, err := PresetConfigPath(name)
+	if err != nil {
+		return patterns, nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return patterns, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ApplyPresetOverrides(patterns, f)
+}
@@ -0,0 +1,60 @@
+package treego
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// previewPrefixLen caps how much of a file is read for its preview.
+const previewPrefixLen = 16 << 10
+
+// FilePreview returns the first n lines of the text file at path, read
+// from a short prefix of it, with trailing carriage returns removed and
+// tabs expanded to four spaces. It returns nil for binary files (those
+// with a NUL byte in the prefix) and files that cannot be read.
+func FilePreview(path string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	head, err := io.ReadAll(io.LimitReader(f, previewPrefixLen))
+	if err != nil || bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(head))
+	for sc.Scan() && len(out) < n {
+		out = append(out, strings.ReplaceAll(strings.TrimRight(sc.Text(), "\r"), "\t", "    "))
+	}
+	return out
+}
+
+// AttachPreviews sets Preview on every regular file under root to its
+// first n lines (see FilePreview), reading the files concurrently. Files
+// marked SkipContent are not read.
+func AttachPreviews(root *Node, n int) {
+	var files []*Node
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		if !node.IsDir {
+			if node.Mode.IsRegular() && !node.SkipContent {
+				files = append(files, node)
+			}
+			return
+		}
+		for _, c := range node.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	parallelEach(len(files), func(i int) {
+		files[i].Preview = FilePreview(files[i].Path, n)
+	})
+}
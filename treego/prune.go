@@ -0,0 +1,201 @@
+package treego
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PruneTree returns a copy of root containing only the entries for which
+// keep returns true, plus the directories leading to them. Unmatched
+// siblings are dropped entirely, and a kept directory only retains the
+// descendants that are kept themselves. It returns nil when nothing below
+// root (or root itself) is kept. The input tree is not modified.
+func PruneTree(root *Node, keep func(n *Node) bool) *Node {
+	return pruneNode(root, keep)
+}
+
+func pruneNode(n *Node, keep func(n *Node) bool) *Node {
+	var children []*Node
+	for _, c := range n.Children {
+		if pc := pruneNode(c, keep); pc != nil {
+			children = append(children, pc)
+		}
+	}
+	if len(children) == 0 && !keep(n) {
+		return nil
+	}
+	out := *n
+	out.Children = children
+	return &out
+}
+
+// PathTo prunes root down to the chains of directories that lead to
+// entries matching any of patterns, which use the --exclude syntax (exact
+// name or path, glob, or "re:<expr>").
+func PathTo(root *Node, patterns []ExcludeMatcher) *Node {
+	return PruneTree(root, func(n *Node) bool {
+		return n != root && shouldExclude(patterns, n.Name, n.Path)
+	})
+}
+
+// FoldIdentical returns a copy of root in which every directory whose
+// subtree has the same shape and names as one printed earlier (sizes and
+// contents are ignored) keeps no children and has SameAs set to the
+// slash-separated path of the first one, relative to root. Empty
+// directories are never folded. The input tree is not modified.
+func FoldIdentical(root *Node) *Node {
+	shapes := make(map[*Node]string)
+	var shape func(n *Node) string
+	shape = func(n *Node) string {
+		h := sha256.New()
+		for _, c := range n.Children {
+			kind := "f"
+			if c.IsDir {
+				kind = "d" + shape(c)
+			}
+			fmt.Fprintf(h, "%q %s\n", c.Name, kind)
+		}
+		shapes[n] = hex.EncodeToString(h.Sum(nil))
+		return shapes[n]
+	}
+	shape(root)
+
+	first := make(map[string]string)
+	var fold func(n *Node, rel string) *Node
+	fold = func(n *Node, rel string) *Node {
+		out := *n
+		if !n.IsDir || len(n.Children) == 0 {
+			return &out
+		}
+		if seen, ok := first[shapes[n]]; ok {
+			out.Children, out.SameAs = nil, seen
+			return &out
+		}
+		first[shapes[n]] = rel
+		out.Children = make([]*Node, len(n.Children))
+		for i, c := range n.Children {
+			childRel := c.Name
+			if rel != "." {
+				childRel = rel + "/" + c.Name
+			}
+			out.Children[i] = fold(c, childRel)
+		}
+		return &out
+	}
+	return fold(root, ".")
+}
+
+// WithoutExtension prunes root down to the files whose name has no
+// extension (see FileType), such as "README" or a mistyped "maingo", and
+// the directories leading to them. Dotfiles like ".gitignore" count as
+// having one. It returns nil when every file has an extension.
+func WithoutExtension(root *Node) *Node {
+	return PruneTree(root, func(n *Node) bool {
+		return !n.IsDir && FileType(n.Name, nil) == ""
+	})
+}
+
+// ReadPathList reads one path per line from r, such as the output of
+// "git diff --name-only". Surrounding spaces, blank lines and lines
+// starting with "#" are skipped.
+func ReadPathList(r io.Reader) ([]string, error) {
+	var paths []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, sc.Err()
+}
+
+// KeepPaths prunes root down to the entries named in paths and the
+// directories leading to them. A listed path names an entry when it equals
+// the entry's Path or its path relative to root; absolute paths are taken
+// relative to root's absolute path. Listed directories are kept without
+// their contents. It returns nil when nothing matched, and the listed
+// paths that matched no entry, in input order.
+func KeepPaths(root *Node, paths []string) (*Node, []string) {
+	rootAbs, _ := filepath.Abs(root.Path)
+	want := make(map[string]bool, len(paths))
+	keys := make([]string, len(paths))
+	for i, p := range paths {
+		if filepath.IsAbs(p) && rootAbs != "" {
+			if rel, err := filepath.Rel(rootAbs, p); err == nil && !strings.HasPrefix(rel, "..") {
+				p = rel
+			}
+		}
+		keys[i] = filepath.ToSlash(filepath.Clean(p))
+		want[keys[i]] = false
+	}
+
+	kept := make(map[*Node]bool)
+	var walk func(n *Node, rel string)
+	walk = func(n *Node, rel string) {
+		for _, key := range []string{rel, filepath.ToSlash(filepath.Clean(n.Path))} {
+			if _, ok := want[key]; ok {
+				kept[n] = true
+				want[key] = true
+			}
+		}
+		for _, c := range n.Children {
+			childRel := c.Name
+			if rel != "." {
+				childRel = rel + "/" + c.Name
+			}
+			walk(c, childRel)
+		}
+	}
+	walk(root, ".")
+
+	var missing []string
+	for i, key := range keys {
+		if !want[key] {
+			missing = append(missing, paths[i])
+			want[key] = true // report duplicates once
+		}
+	}
+	if len(kept) == 0 {
+		return nil, missing
+	}
+	return PruneTree(root, func(n *Node) bool { return kept[n] }), missing
+}
+
+// ModifiedSince prunes root down to the files modified at or after cutoff
+// and the directories leading to them. It returns nil when none match.
+func ModifiedSince(root *Node, cutoff time.Time) *Node {
+	return PruneTree(root, func(n *Node) bool {
+		return !n.IsDir && !n.ModTime.Before(cutoff)
+	})
+}
+
+// ParseRecency parses a positive recency window such as "90m", "2h" or
+// "7d". It accepts everything time.ParseDuration does, plus whole days (d)
+// and weeks (w) on their own ("1d12h" is not supported; use "36h").
+func ParseRecency(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if unit := strings.TrimLeft(s, "0123456789"); unit == "d" || unit == "w" {
+		var n int64
+		n, err = strconv.ParseInt(strings.TrimSuffix(s, unit), 10, 64)
+		d = time.Duration(n) * 24 * time.Hour
+		if unit == "w" {
+			d *= 7
+		}
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (want e.g. 30m, 2h, 7d or 2w)", s)
+	}
+	return d, nil
+}
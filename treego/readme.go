@@ -0,0 +1,73 @@
+package treego
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadmePreviewLines is the number of lines ReadmePreview returns.
+const ReadmePreviewLines = 2
+
+// readmePrefixLen caps how much of a README is read for its preview.
+const readmePrefixLen = 4096
+
+// IsReadme reports whether name is a README file in any case, with or
+// without an extension: README, readme.md, ReadMe.rst and so on.
+func IsReadme(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "readme" || strings.HasPrefix(lower, "readme.")
+}
+
+// ReadmePreview returns the first ReadmePreviewLines lines of prose in the
+// file at path, read from a short prefix of it. Blank lines, heading
+// underlines and badge images are skipped, and Markdown heading markers
+// are removed. It returns nil when the file cannot be read.
+func ReadmePreview(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var out []string
+	sc := bufio.NewScanner(io.LimitReader(f, readmePrefixLen))
+	for sc.Scan() && len(out) < ReadmePreviewLines {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.Trim(line, "=-~") == "" || strings.HasPrefix(line, "![") || strings.HasPrefix(line, "[![") || strings.HasPrefix(line, "<") {
+			continue
+		}
+		if t := strings.TrimSpace(strings.TrimLeft(line, "#")); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// AttachReadmePreviews sets Readme on every directory under root, root
+// included, that holds a README file, reading the files concurrently.
+// When a directory has several, the first in its listing order is used;
+// README files that are not regular files or are marked SkipContent are
+// not read.
+func AttachReadmePreviews(root *Node) {
+	var dirs, readmes []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			return
+		}
+		for _, c := range n.Children {
+			if !c.IsDir && c.Mode.IsRegular() && !c.SkipContent && IsReadme(c.Name) {
+				dirs, readmes = append(dirs, n), append(readmes, c)
+				break
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	parallelEach(len(dirs), func(i int) {
+		dirs[i].Readme = ReadmePreview(readmes[i].Path)
+	})
+}
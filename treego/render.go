@@ -0,0 +1,155 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Renderer writes a tree to w in a specific output format.
+type Renderer interface {
+	Render(node *Node, w io.Writer, opts Options) error
+}
+
+// RendererFunc adapts an ordinary function to the Renderer interface.
+type RendererFunc func(node *Node, w io.Writer, opts Options) error
+
+func (f RendererFunc) Render(node *Node, w io.Writer, opts Options) error {
+	return f(node, w, opts)
+}
+
+// DefaultFormat is the format used when none is selected.
+const DefaultFormat = "tree"
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = make(map[string]Renderer)
+)
+
+func init() {
+	RegisterRenderer("tree", RendererFunc(renderTree))
+	RegisterRenderer("json", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToJSON(node, w) }))
+	RegisterRenderer("ndjson", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToNDJSON(node, w) }))
+	RegisterRenderer("yaml", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToYAML(node, w) }))
+	RegisterRenderer("html", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToHTML(node, w) }))
+	RegisterRenderer("dot", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToDOT(node, w) }))
+	RegisterRenderer("tree-json", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToTreeJSON(node, w) }))
+	RegisterRenderer("plantuml", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToPlantUML(node, w) }))
+	RegisterRenderer("csv", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToCSV(node, w) }))
+	RegisterRenderer("manifest", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return WriteManifest(node, w) }))
+	RegisterRenderer("markdown", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToMarkdown(node, w) }))
+	RegisterRenderer("sql", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToSQL(node, w) }))
+	RegisterRenderer("horizontal", RendererFunc(TreeToHorizontal))
+	RegisterRenderer("rst", RendererFunc(func(node *Node, w io.Writer, _ Options) error { return TreeToRST(node, w) }))
+}
+
+// machineFormats are the built-in formats meant to be read by programs.
+var machineFormats = map[string]bool{
+	"json": true, "tree-json": true, "ndjson": true, "yaml": true, "csv": true, "manifest": true, "sql": true,
+}
+
+// IsMachineFormat reports whether name is a built-in format meant for
+// programs rather than people. The CLI orders these deterministically
+// (see Options.Deterministic) by default, so their output is reproducible.
+func IsMachineFormat(name string) bool {
+	return machineFormats[name]
+}
+
+// RegisterRenderer makes a renderer available under the given format name.
+// Registering a name that already exists replaces the previous renderer,
+// which lets library users override the built-in formats.
+func RegisterRenderer(name string, r Renderer) {
+	if name == "" {
+		panic("treego: RegisterRenderer called with empty name")
+	}
+	if r == nil {
+		panic("treego: RegisterRenderer called with nil renderer for " + name)
+	}
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = r
+}
+
+// LookupRenderer returns the renderer registered under name.
+func LookupRenderer(name string) (Renderer, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	r, ok := renderers[name]
+	return r, ok
+}
+
+// RendererNames returns the registered format names in sorted order.
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render writes node to w using the renderer registered for format.
+func Render(node *Node, w io.Writer, format string, opts Options) error {
+	r, ok := LookupRenderer(format)
+	if !ok {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return r.Render(node, w, opts)
+}
+
+// header writes the root line of a tree print and the root's README
+// preview.
+func (p *treePrinter) header(node *Node) error {
+	label := node.Name
+	if p.opts.RootLabel != "" {
+		label = p.opts.RootLabel
+	}
+	label = p.style.name(node, label)
+	if node.Truncated {
+		label += " [truncated]"
+	}
+	if len(p.opts.Columns) > 0 {
+		label = p.withCounts(node, label)
+	} else {
+		label = p.withTotals(node, label)
+	}
+	if err := p.row(node, label); err != nil {
+		return err
+	}
+	return p.readme(node, "", true)
+}
+
+// renderTree prints the root label followed by the box-drawing tree.
+func renderTree(node *Node, w io.Writer, opts Options) error {
+	p := newTreePrinter(w, opts)
+	if opts.Heatmap {
+		if p.style.color {
+			p.heatMax = maxFileSize(node)
+		} else {
+			// Without colors the gradient falls back to numeric sizes.
+			p.opts.ShowSize = true
+		}
+	}
+	if (opts.ShowDirSize || opts.ShowCounts) && node.Totals == nil {
+		AggregateAll(node)
+	}
+	if err := p.header(node); err != nil {
+		return err
+	}
+	if err := p.print(node, "", ""); err != nil {
+		return err
+	}
+	if len(p.opts.Columns) > 0 {
+		if err := p.flushTable(); err != nil {
+			return err
+		}
+	}
+	if p.heatMax > 0 {
+		_, err := fmt.Fprintln(w, "\n"+heatLegend(p.heatMax, opts.sizeBase()))
+		return err
+	}
+	return nil
+}
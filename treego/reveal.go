@@ -0,0 +1,78 @@
+package treego
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RevealFrames runs root through the pipeline like RenderNode and renders
+// it once per level: frame i shows the entries at most i+1 levels below the
+// root, in the tree format. A tree without children yields a single frame.
+func RevealFrames(root *Node, opts Options) ([]string, error) {
+	if root = PrepareTree(root, opts); root == nil {
+		return []string{""}, nil
+	}
+	depth := treeDepth(root)
+	if opts.MaxDepth > 0 && opts.MaxDepth < depth {
+		depth = opts.MaxDepth
+	}
+	if depth == 0 {
+		depth = 1
+	}
+	frames := make([]string, 0, depth)
+	for d := 1; d <= depth; d++ {
+		var buf bytes.Buffer
+		opts.MaxDepth = d
+		if err := renderTree(root, &buf, opts); err != nil {
+			return nil, err
+		}
+		frames = append(frames, buf.String())
+	}
+	return frames, nil
+}
+
+// Reveal animates the tree on a terminal, drawing one more level every
+// delay by redrawing the previous frame in place. When w is not a
+// terminal only the complete tree is written. opts.Color is decided for w,
+// as by the other renderers.
+func Reveal(w io.Writer, root *Node, opts Options, delay time.Duration) error {
+	// The frames are drawn into buffers, which are never terminals.
+	opts.Color = ResolveColor(w, opts.Color)
+	frames, err := RevealFrames(root, opts)
+	if err != nil {
+		return err
+	}
+	if !IsTerminal(w) {
+		_, err := io.WriteString(w, frames[len(frames)-1])
+		return err
+	}
+	prev := 0
+	for i, frame := range frames {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+		if prev > 0 {
+			// Move to the start of the previous frame and clear below.
+			frame = fmt.Sprintf("\x1b[%dF\x1b[J", prev) + frame
+		}
+		if _, err := io.WriteString(w, frame); err != nil {
+			return err
+		}
+		prev = strings.Count(frames[i], "\n")
+	}
+	return nil
+}
+
+// treeDepth returns how many levels lie below n.
+func treeDepth(n *Node) int {
+	depth := 0
+	for _, c := range n.Children {
+		if d := treeDepth(c) + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
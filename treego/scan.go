@@ -0,0 +1,125 @@
+package treego
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// ScanResult bundles everything a scan produces.
+type ScanResult struct {
+	// Root is the built tree, or nil when the root was excluded or the
+	// scan failed.
+	Root *Node
+	// Errors lists the filesystem errors hit during the scan, in no
+	// particular order. An entry that fails to stat or, for a directory,
+	// to list is left out of Root, and the scan goes on with the others;
+	// Root is nil only when the root itself could not be read.
+	Errors []error
+	// Partial reports that the context ended before the scan finished;
+	// directories that were not fully read are marked Truncated.
+	Partial bool
+
+	// Totals of the entries kept by the build, counted during traversal
+	// (so they include files dropped by Options.SummaryOnly).
+	Dirs  int   // directories, including the root
+	Files int   // files
+	Bytes int64 // total size of the files
+
+	Duration time.Duration
+}
+
+// Scan builds the tree rooted at path like BuildFilteredTree and returns it
+// together with counts, errors and timing. The returned error is the first
+// entry of Errors, if any; the result is non-nil either way, and holds
+// everything that could be read when only some entries failed.
+func Scan(path string, opts Options) (*ScanResult, error) {
+	return ScanContext(context.Background(), path, opts)
+}
+
+// ScanContext is Scan with cancellation, as in BuildTreeContext.
+func ScanContext(ctx context.Context, path string, opts Options) (*ScanResult, error) {
+	start := time.Now()
+	b := newBuilder(ctx, opts)
+	res := b.result(b.run(path), start)
+	if len(res.Errors) > 0 {
+		return res, res.Errors[0]
+	}
+	return res, nil
+}
+
+// RenderProgressive scans path like ScanContext and prints it in the tree
+// format while the scan runs: each top-level entry is written as soon as
+// it and every entry before it are fully built, so output starts before
+// the whole tree is read. The output is the same as rendering the finished
+// tree. Options that need the whole tree before the first line (SortHot,
+// Heatmap, ShowDirSize, ShowCounts, SummaryOnly and Columns, which aligns
+// every line) print it once the scan ends instead.
+// The error is the first write error; scan errors are reported in the
+// result, which is non-nil either way.
+func RenderProgressive(ctx context.Context, w io.Writer, path string, opts Options) (*ScanResult, error) {
+	start := time.Now()
+	b := newBuilder(ctx, opts)
+	var (
+		streamed bool
+		err      error
+	)
+	hot := opts.Sort == SortHot && !opts.Deterministic
+	if !hot && !opts.Heatmap && !opts.ShowDirSize && !opts.ShowCounts && !opts.SummaryOnly && len(opts.Columns) == 0 {
+		p := newTreePrinter(w, opts)
+		b.stream = func(root *Node, entries []*Node, wait func(*Node) *Node) {
+			streamed = true
+			if err = p.header(root); err == nil {
+				err = p.printChildren(entries, "", "", wait)
+			}
+		}
+	}
+	res := b.result(b.run(path), start)
+	if !streamed && res.Root != nil {
+		// A file root, a root cut short before its listing, or options
+		// that need the finished tree.
+		err = renderTree(res.Root, w, opts)
+	}
+	return res, err
+}
+
+// result collects the outcome of a build that started at start.
+func (b *builder) result(root *Node, start time.Time) *ScanResult {
+	return &ScanResult{
+		Root:     root,
+		Errors:   b.errs,
+		Partial:  b.partial.Load(),
+		Dirs:     int(b.dirs.Load()),
+		Files:    int(b.files.Load()),
+		Bytes:    b.bytes.Load(),
+		Duration: time.Since(start),
+	}
+}
+
+// errorRecord is one line of WriteErrorsJSON output.
+type errorRecord struct {
+	Path    string `json:"path,omitempty"`
+	Op      string `json:"op,omitempty"`
+	Message string `json:"message"`
+}
+
+// WriteErrorsJSON writes errs as JSON Lines, one {"path", "op", "message"}
+// object per error. Path and op are filled in for filesystem errors
+// (*fs.PathError) and omitted otherwise.
+func WriteErrorsJSON(w io.Writer, errs []error) error {
+	enc := json.NewEncoder(w)
+	for _, err := range errs {
+		rec := errorRecord{Message: err.Error()}
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			rec = errorRecord{Path: pe.Path, Op: pe.Op, Message: pe.Err.Error()}
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
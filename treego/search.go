@@ -0,0 +1,142 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// nameContains is the case-insensitive substring test used by --search.
+func nameContains(name, query string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(query))
+}
+
+// PrintSearch writes the path of every entry under (and including) node
+// whose name contains query, in tree order.
+func PrintSearch(w io.Writer, node *Node, query string) error {
+	ew := &errWriter{w: w}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if nameContains(n.Name, query) {
+			ew.printf("%s\n", n.Path)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(node)
+	return ew.err
+}
+
+// PrintLevel writes the entries exactly depth levels below root (its
+// children are at depth 1) as a flat list, one slash-separated path
+// relative to root per line in tree order. Directory paths end with a
+// slash; files are skipped when opts.DirsOnly is set.
+func PrintLevel(w io.Writer, root *Node, depth int, opts Options) error {
+	ew := &errWriter{w: w}
+	var walk func(n *Node, rel string, level int)
+	walk = func(n *Node, rel string, level int) {
+		if level == depth {
+			if n.IsDir {
+				ew.printf("%s/\n", rel)
+			} else if !opts.DirsOnly {
+				ew.printf("%s\n", rel)
+			}
+			return
+		}
+		for _, c := range n.Children {
+			childRel := c.Name
+			if rel != "" {
+				childRel = rel + "/" + c.Name
+			}
+			walk(c, childRel, level+1)
+		}
+	}
+	if depth > 0 {
+		walk(root, "", 0)
+	}
+	return ew.err
+}
+
+// RebasePaths rewrites every Path under root relative to base, so printed
+// paths can be used directly from that directory. Both sides are made
+// absolute first; a path that cannot be expressed relative to base (e.g. on
+// another Windows drive) is left absolute.
+func RebasePaths(root *Node, base string) error {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return err
+	}
+	var walk func(n *Node) error
+	walk = func(n *Node) error {
+		abs, err := filepath.Abs(n.Path)
+		if err != nil {
+			return err
+		}
+		n.Path = abs
+		if rel, err := filepath.Rel(base, abs); err == nil {
+			n.Path = rel
+		}
+		for _, c := range n.Children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+// DirCount is a directory together with the number of its descendants
+// whose name matches a search query.
+type DirCount struct {
+	Node  *Node
+	Count int
+}
+
+// SearchCounts returns, in tree order, every directory under (and
+// including) node that has at least one matching descendant. Counts are
+// computed in a single post-order pass.
+func SearchCounts(node *Node, query string) []DirCount {
+	var out []DirCount
+	countMatches(node, query, &out)
+	return out
+}
+
+// countMatches returns the number of matches at or below node and appends
+// node's own entry to out, ahead of its descendants, when it has any.
+func countMatches(node *Node, query string, out *[]DirCount) int {
+	self := 0
+	if nameContains(node.Name, query) {
+		self = 1
+	}
+	if !node.IsDir {
+		return self
+	}
+
+	pos := len(*out)
+	*out = append(*out, DirCount{Node: node})
+	total := 0
+	for _, child := range node.Children {
+		total += countMatches(child, query, out)
+	}
+	if total == 0 {
+		// No matches below: drop the placeholder (descendants added nothing).
+		*out = (*out)[:pos]
+	} else {
+		(*out)[pos].Count = total
+	}
+	return total + self
+}
+
+// PrintSearchCounts writes one line per directory with matches, as
+// "<count>  <path>".
+func PrintSearchCounts(w io.Writer, counts []DirCount) error {
+	for _, c := range counts {
+		if _, err := fmt.Fprintf(w, "%7d  %s\n", c.Count, c.Node.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
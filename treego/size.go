@@ -0,0 +1,67 @@
+package treego
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Unit bases accepted by HumanizeSize.
+const (
+	// BinaryBase yields KiB, MiB, GiB, ... (the default).
+	BinaryBase int64 = 1024
+	// SIBase yields KB, MB, GB, ...
+	SIBase int64 = 1000
+)
+
+var (
+	binaryUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits     = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// HumanizeSize formats n bytes with one decimal in the largest fitting unit
+// of base, which must be SIBase or BinaryBase (anything else is treated as
+// BinaryBase). Values below one unit are printed as whole bytes.
+func HumanizeSize(n int64, base int64) string {
+	units := binaryUnits
+	if base == SIBase {
+		units = siUnits
+	} else {
+		base = BinaryBase
+	}
+	if n < base && n > -base {
+		return fmt.Sprintf("%d %s", n, units[0])
+	}
+	v := float64(n)
+	i := 0
+	for (v >= float64(base) || v <= -float64(base)) && i < len(units)-1 {
+		v /= float64(base)
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
+
+var sizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kib": 1 << 10, "kb": 1e3,
+	"m": 1 << 20, "mib": 1 << 20, "mb": 1e6,
+	"g": 1 << 30, "gib": 1 << 30, "gb": 1e9,
+	"t": 1 << 40, "tib": 1 << 40, "tb": 1e12,
+}
+
+// ParseSize parses a size such as "512", "1.5M" or "100MiB" into bytes.
+// Units are B, K/KiB, M/MiB, G/GiB, T/TiB (1024-based) and KB, MB, GB, TB
+// (1000-based), in any case.
+func ParseSize(s string) (int64, error) {
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+	num, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := sizeUnits[strings.ToLower(s[i:])]
+	if err != nil || !ok {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(num * float64(unit)), nil
+}
@@ -0,0 +1,68 @@
+package treego
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TreeFromJSON decodes a tree written by TreeToJSON. Every field survives
+// the round trip, so the result can be rendered with any option as if it
+// had just been scanned; filesystem times are returned in the local time
+// zone, like a scan's.
+func TreeFromJSON(r io.Reader) (*Node, error) {
+	var node Node
+	if err := json.NewDecoder(r).Decode(&node); err != nil {
+		return nil, err
+	}
+	localizeTimes(&node)
+	return &node, nil
+}
+
+// localizeTimes converts the filesystem times under n to local time.
+func localizeTimes(n *Node) {
+	if !n.ModTime.IsZero() {
+		n.ModTime = n.ModTime.Local()
+	}
+	for _, t := range []*time.Time{n.AccessTime, n.ChangeTime} {
+		if t != nil {
+			*t = t.Local()
+		}
+	}
+	for _, c := range n.Children {
+		localizeTimes(c)
+	}
+}
+
+// LoadSnapshot reads a tree previously stored with SaveSnapshot.
+// A missing file returns an error satisfying os.IsNotExist.
+func LoadSnapshot(path string) (*Node, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return TreeFromJSON(f)
+}
+
+// SaveSnapshot stores node as JSON at path. The file is written to a
+// temporary sibling first and renamed, so an interrupted run never leaves
+// a truncated snapshot behind.
+func SaveSnapshot(path string, node *Node) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(node); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
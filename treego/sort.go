@@ -0,0 +1,232 @@
+package treego
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortMode selects how entries within a directory are ordered.
+// Directories always come before files.
+type SortMode int
+
+const (
+	// SortName orders by name (the default).
+	SortName SortMode = iota
+	// SortNatural orders by name, comparing embedded numbers numerically
+	// so "file2" sorts before "file10".
+	SortNatural
+	// SortSize orders by size, largest first.
+	SortSize
+	// SortMtime orders by modification time, newest first.
+	SortMtime
+	// SortExt orders files by extension, then by name, so files of one type
+	// cluster together. Files without an extension come first; directories
+	// are ordered by name.
+	SortExt
+	// SortHot orders entries by the newest modification time anywhere in
+	// their subtree, newest first, so directories holding recent changes
+	// come first.
+	SortHot
+)
+
+// ParseSortMode parses "name", "natural", "size", "mtime", "ext" or "hot".
+func ParseSortMode(s string) (SortMode, error) {
+	switch s {
+	case "", "name":
+		return SortName, nil
+	case "natural":
+		return SortNatural, nil
+	case "size":
+		return SortSize, nil
+	case "mtime":
+		return SortMtime, nil
+	case "ext":
+		return SortExt, nil
+	case "hot":
+		return SortHot, nil
+	default:
+		return SortName, fmt.Errorf("invalid sort mode %q (want name, natural, size, mtime, ext or hot)", s)
+	}
+}
+
+// SortNodes orders entries in place: directories first, then files, both
+// by opts.Sort. Names compare case-insensitively unless opts.SortCaseSensitive
+// is set, so "Readme" sits next to "readme"; ties fall back to the original
+// name, which keeps the order stable across runs and filesystems. With
+// opts.Deterministic the canonical order is used instead.
+func SortNodes(nodes []*Node, opts Options) {
+	var latest map[*Node]time.Time
+	if opts.Sort == SortHot && !opts.Deterministic {
+		latest = make(map[*Node]time.Time, len(nodes))
+		for _, n := range nodes {
+			latest[n] = LatestModTime(n)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		if opts.Deterministic {
+			return a.Name < b.Name
+		}
+		switch opts.Sort {
+		case SortSize:
+			if a.Size != b.Size {
+				return a.Size > b.Size
+			}
+		case SortMtime:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.After(b.ModTime)
+			}
+		case SortNatural:
+			return naturalLess(a.Name, b.Name, opts.SortCaseSensitive)
+		case SortExt:
+			if ea, eb := sortExt(a, opts.ExtGroups), sortExt(b, opts.ExtGroups); ea != eb {
+				return ea < eb
+			}
+		case SortHot:
+			if ta, tb := latest[a], latest[b]; !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+		}
+		return nameLess(a.Name, b.Name, opts.SortCaseSensitive)
+	})
+}
+
+// LatestModTime returns the newest ModTime of n and everything below it.
+func LatestModTime(n *Node) time.Time {
+	latest := n.ModTime
+	for _, c := range n.Children {
+		if t := LatestModTime(c); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// RankRecent sets Rank on every file under root by modification time
+// across the whole tree: 1 for the most recently modified, 2 for the next,
+// and so on. Files with equal times share a rank, the next one skipping
+// ahead ("1, 2, 2, 4"). When top is positive only files ranked top or
+// better keep a rank; the rest are left at zero.
+func RankRecent(root *Node, top int) {
+	var files []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			files = append(files, n)
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].ModTime.After(files[j].ModTime)
+	})
+	for i, f := range files {
+		f.Rank = i + 1
+		if i > 0 && f.ModTime.Equal(files[i-1].ModTime) {
+			f.Rank = files[i-1].Rank
+		}
+	}
+	if top > 0 {
+		for _, f := range files {
+			if f.Rank > top {
+				f.Rank = 0
+			}
+		}
+	}
+}
+
+// sortExt is the SortExt key: the file type of a file (see FileType), or
+// "" for directories.
+func sortExt(n *Node, groups map[string]string) string {
+	if n.IsDir {
+		return ""
+	}
+	return FileType(n.Name, groups)
+}
+
+// nameLess compares names case-insensitively first (unless caseSensitive),
+// breaking ties on the raw byte order.
+func nameLess(a, b string, caseSensitive bool) bool {
+	if !caseSensitive {
+		la, lb := strings.ToLower(a), strings.ToLower(b)
+		if la != lb {
+			return la < lb
+		}
+	}
+	return a < b
+}
+
+// NaturalLess reports whether a sorts before b in natural order: names are
+// split into digit and non-digit runs, digit runs compare by numeric value
+// and text runs compare case-insensitively.
+func NaturalLess(a, b string) bool {
+	return naturalLess(a, b, false)
+}
+
+func naturalLess(a, b string, caseSensitive bool) bool {
+	x, y := a, b
+	if !caseSensitive {
+		x, y = strings.ToLower(a), strings.ToLower(b)
+	}
+	for x != "" && y != "" {
+		xr, xrest, xnum := nextRun(x)
+		yr, yrest, ynum := nextRun(y)
+		switch {
+		case xnum && ynum:
+			if c := compareDigits(xr, yr); c != 0 {
+				return c < 0
+			}
+		case xr != yr:
+			return xr < yr
+		}
+		x, y = xrest, yrest
+	}
+	if x != y {
+		return x == ""
+	}
+	// Equal in natural order ("a01" vs "a1"): fall back to the raw names.
+	return a < b
+}
+
+// nextRun splits off the leading run of digits or non-digits.
+func nextRun(s string) (run, rest string, digits bool) {
+	digits = isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:], digits
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// compareDigits compares two digit runs by numeric value without parsing,
+// so arbitrarily long numbers never overflow.
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// entryLess is the default entry order used where only a name and type are
+// at hand: directories first, then case-insensitive name.
+func entryLess(aIsDir bool, aName string, bIsDir bool, bName string) bool {
+	if aIsDir != bIsDir {
+		return aIsDir
+	}
+	return nameLess(aName, bName, false)
+}
@@ -0,0 +1,55 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SpinnerDelay is how long StartSpinner waits before drawing anything, so
+// fast scans finish without flicker.
+const SpinnerDelay = 200 * time.Millisecond
+
+// spinnerFrames are drawn in turn, one per spinnerInterval.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 80 * time.Millisecond
+
+// StartSpinner shows an activity indicator followed by label on w until
+// the returned stop function is called, which clears the line again. It
+// draws only when w is a terminal (and TERM is not "dumb") and the work
+// outlasts SpinnerDelay; otherwise it writes nothing. stop waits for the
+// indicator to be cleared and may be called more than once.
+func StartSpinner(w io.Writer, label string) (stop func()) {
+	if !IsTerminal(w) || os.Getenv("TERM") == "dumb" {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-done:
+			return
+		case <-time.After(SpinnerDelay):
+		}
+		tick := time.NewTicker(spinnerInterval)
+		defer tick.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(w, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], label)
+			select {
+			case <-done:
+				fmt.Fprint(w, "\r"+ansiClearLine)
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-finished
+	}
+}
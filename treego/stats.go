@@ -0,0 +1,291 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NoExt is the ExtStat.Ext value for files without an extension.
+const NoExt = "(none)"
+
+// ExtStat aggregates the files sharing one extension.
+type ExtStat struct {
+	Ext   string // lower-case, without the leading dot, a group name, or NoExt
+	Files int
+	Bytes int64
+}
+
+// FileType returns the lower-case extension of name without the dot,
+// replaced by its group when groups maps it (see ParseExtGroups), or ""
+// when name has no extension.
+func FileType(name string, groups map[string]string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	if g, ok := groups[ext]; ok && ext != "" {
+		return g
+	}
+	return ext
+}
+
+// ParseExtGroups parses comma-separated ext=group pairs such as
+// "jpg=image,jpeg=image", as accepted by --ext-group, into a map from
+// lower-case extension (without the dot) to group name.
+func ParseExtGroups(specs []string) (map[string]string, error) {
+	groups := make(map[string]string)
+	for _, spec := range specs {
+		for _, pair := range strings.Split(spec, ",") {
+			ext, group, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+			group = strings.TrimSpace(group)
+			if !ok || ext == "" || group == "" {
+				return nil, fmt.Errorf("invalid extension group %q (want ext=group)", pair)
+			}
+			groups[ext] = group
+		}
+	}
+	return groups, nil
+}
+
+// ExtStats groups the files under root by extension, largest total size
+// first; ties are ordered by extension.
+func ExtStats(root *Node) []ExtStat {
+	return GroupedExtStats(root, nil)
+}
+
+// GroupedExtStats is ExtStats with the extensions in groups counted under
+// their group name (see ParseExtGroups).
+func GroupedExtStats(root *Node, groups map[string]string) []ExtStat {
+	byExt := make(map[string]*ExtStat)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			ext := FileType(n.Name, groups)
+			if ext == "" {
+				ext = NoExt
+			}
+			s := byExt[ext]
+			if s == nil {
+				s = &ExtStat{Ext: ext}
+				byExt[ext] = s
+			}
+			s.Files++
+			s.Bytes += n.Size
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	out := make([]ExtStat, 0, len(byExt))
+	for _, s := range byExt {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Ext < out[j].Ext
+	})
+	return out
+}
+
+const (
+	// compositionWidth is the number of cells in the composition bar.
+	compositionWidth = 40
+	// compositionTop is how many extensions get their own segment; the
+	// rest are folded into "other".
+	compositionTop = 4
+)
+
+// compositionColors are the segment colors, in order of size.
+var compositionColors = []string{ansiBlue, ansiGreen, ansiYellow, ansiMagenta, ansiDimGray}
+
+// PrintComposition writes a one-line summary of the bytes under root by
+// file type, with opts.ExtGroups applied. With colors enabled it is a bar
+// of colored segments followed by their labels; otherwise it is a textual
+// percentage breakdown. Nothing is written when the tree holds no bytes.
+func PrintComposition(w io.Writer, root *Node, opts Options) error {
+	stats := GroupedExtStats(root, opts.ExtGroups)
+	var total int64
+	for _, s := range stats {
+		total += s.Bytes
+	}
+	if total == 0 {
+		return nil
+	}
+	if len(stats) > compositionTop+1 {
+		other := ExtStat{Ext: "other"}
+		for _, s := range stats[compositionTop:] {
+			other.Files += s.Files
+			other.Bytes += s.Bytes
+		}
+		stats = append(stats[:compositionTop:compositionTop], other)
+	}
+
+	var b strings.Builder
+	if !newStyle(w, opts).color {
+		for i, s := range stats {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			fmt.Fprintf(&b, "%s %.1f%%", s.Ext, float64(s.Bytes)*100/float64(total))
+		}
+	} else {
+		for i, s := range stats {
+			cells := int(s.Bytes * compositionWidth / total)
+			if cells == 0 {
+				cells = 1
+			}
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(compositionColors[i] + strings.Repeat("█", cells) + ansiReset + " " + s.Ext)
+		}
+	}
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+// DepthExtremes summarizes the shape of a tree by file depth, where a file
+// directly under the root has depth 1. Paths are relative to the root.
+type DepthExtremes struct {
+	Files        int
+	Deepest      string
+	MaxDepth     int
+	Shallowest   string
+	MinDepth     int
+	AverageDepth float64
+}
+
+// FindDepthExtremes returns the deepest and shallowest file under root and
+// the average file depth. Ties go to the first file in tree order.
+func FindDepthExtremes(root *Node) DepthExtremes {
+	var e DepthExtremes
+	total := 0
+	for _, f := range relFiles(root) {
+		depth := strings.Count(f.Rel, "/") + 1
+		if e.Files == 0 || depth > e.MaxDepth {
+			e.Deepest, e.MaxDepth = f.Rel, depth
+		}
+		if e.Files == 0 || depth < e.MinDepth {
+			e.Shallowest, e.MinDepth = f.Rel, depth
+		}
+		e.Files++
+		total += depth
+	}
+	if e.Files > 0 {
+		e.AverageDepth = float64(total) / float64(e.Files)
+	}
+	return e
+}
+
+// PrintDepthExtremes writes e as a small summary block.
+func PrintDepthExtremes(w io.Writer, e DepthExtremes) error {
+	if e.Files == 0 {
+		_, err := fmt.Fprintln(w, "No files found")
+		return err
+	}
+	ew := &errWriter{w: w}
+	ew.printf("Deepest:    %s (depth %d)\n", e.Deepest, e.MaxDepth)
+	ew.printf("Shallowest: %s (depth %d)\n", e.Shallowest, e.MinDepth)
+	ew.printf("Average:    %.2f across %d files\n", e.AverageDepth, e.Files)
+	return ew.err
+}
+
+// SizeBucket counts the files whose size falls in [Min, Max).
+type SizeBucket struct {
+	Min, Max int64
+	Files    int
+}
+
+// SizeHistogram buckets the files under root by powers of ten: [0, 1K),
+// [1K, 10K), [10K, 100K), and so on (1K = 1000 bytes). Buckets run from the
+// first to the last non-empty one; nil is returned for a tree without files.
+func SizeHistogram(root *Node) []SizeBucket {
+	var buckets []SizeBucket
+	first := -1
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			i := sizeBucketIndex(n.Size)
+			for len(buckets) <= i {
+				min := int64(0)
+				if k := len(buckets); k > 0 {
+					min = buckets[k-1].Max
+				}
+				buckets = append(buckets, SizeBucket{Min: min, Max: sizeBucketMax(len(buckets))})
+			}
+			buckets[i].Files++
+			if first < 0 || i < first {
+				first = i
+			}
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	if first < 0 {
+		return nil
+	}
+	return buckets[first:]
+}
+
+// sizeBucketMax returns the exclusive upper bound of bucket i: 1000 * 10^i.
+func sizeBucketMax(i int) int64 {
+	max := int64(1000)
+	for ; i > 0; i-- {
+		max *= 10
+	}
+	return max
+}
+
+func sizeBucketIndex(size int64) int {
+	i := 0
+	for max := int64(1000); size >= max && i < 15; max *= 10 {
+		i++
+	}
+	return i
+}
+
+// histogramWidth is the length of the longest bar in PrintSizeHistogram.
+const histogramWidth = 30
+
+// PrintSizeHistogram writes buckets as a bar chart, one line per bucket.
+func PrintSizeHistogram(w io.Writer, buckets []SizeBucket) error {
+	if len(buckets) == 0 {
+		_, err := fmt.Fprintln(w, "No files found")
+		return err
+	}
+	most := 0
+	for _, b := range buckets {
+		if b.Files > most {
+			most = b.Files
+		}
+	}
+	ew := &errWriter{w: w}
+	for _, b := range buckets {
+		bar := strings.Repeat("█", b.Files*histogramWidth/most)
+		if bar == "" && b.Files > 0 {
+			bar = "▏"
+		}
+		ew.printf("%5s – %-5s %s %d\n", compactSize(b.Min), compactSize(b.Max), bar, b.Files)
+	}
+	return ew.err
+}
+
+// compactSize formats a power-of-ten bucket bound as 0, 1K, 10K, 1M, ...
+func compactSize(n int64) string {
+	units := []string{"", "K", "M", "G", "T", "P", "E"}
+	i := 0
+	for n >= 1000 && n%1000 == 0 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	return fmt.Sprintf("%d%s", n, units[i])
+}
@@ -0,0 +1,149 @@
+package treego
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Symbol is a top-level declaration found in a source file.
+type Symbol struct {
+	// Kind is "func", "method" or "type".
+	Kind string `json:"kind"`
+	// Name is the declared name; methods are qualified by their receiver
+	// type, as in "Builder.Run".
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// SymbolExtractor lists the top-level declarations of a source file.
+type SymbolExtractor interface {
+	Symbols(path string) ([]Symbol, error)
+}
+
+// SymbolExtractorFunc adapts an ordinary function to the SymbolExtractor
+// interface.
+type SymbolExtractorFunc func(path string) ([]Symbol, error)
+
+func (f SymbolExtractorFunc) Symbols(path string) ([]Symbol, error) {
+	return f(path)
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = make(map[string]SymbolExtractor)
+)
+
+func init() {
+	RegisterSymbolExtractor("go", SymbolExtractorFunc(goSymbols))
+}
+
+// RegisterSymbolExtractor makes e the extractor for files with extension
+// ext ("go" and ".go" are equivalent). Registering an extension again
+// replaces the previous extractor.
+func RegisterSymbolExtractor(ext string, e SymbolExtractor) {
+	ext = strings.TrimPrefix(strings.ToLower(ext), ".")
+	if ext == "" {
+		panic("treego: RegisterSymbolExtractor called with empty extension")
+	}
+	if e == nil {
+		panic("treego: RegisterSymbolExtractor called with nil extractor for " + ext)
+	}
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors[ext] = e
+}
+
+// LookupSymbolExtractor returns the extractor registered for the extension
+// of name, if any.
+func LookupSymbolExtractor(name string) (SymbolExtractor, bool) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	e, ok := extractors[ext]
+	return e, ok
+}
+
+// ExtractSymbols sets Symbols on every regular file under root that has a
+// registered extractor, parsing the files concurrently. Files that fail
+// to parse or are marked SkipContent are left without symbols.
+func ExtractSymbols(root *Node) {
+	type target struct {
+		node *Node
+		e    SymbolExtractor
+	}
+	var jobs []target
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			if e, ok := LookupSymbolExtractor(n.Name); ok && n.Mode.IsRegular() && !n.SkipContent {
+				jobs = append(jobs, target{n, e})
+			}
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	parallelEach(len(jobs), func(i int) {
+		if syms, err := jobs[i].e.Symbols(jobs[i].node.Path); err == nil {
+			jobs[i].node.Symbols = syms
+		}
+	})
+}
+
+// goSymbols lists the functions, methods and types declared at the top
+// level of a Go source file, in source order.
+func goSymbols(path string) ([]Symbol, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var out []Symbol
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			s := Symbol{Kind: "func", Name: d.Name.Name, Line: fset.Position(d.Pos()).Line}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				s.Kind = "method"
+				s.Name = receiverName(d.Recv.List[0].Type) + "." + s.Name
+			}
+			out = append(out, s)
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				out = append(out, Symbol{Kind: "type", Name: ts.Name.Name, Line: fset.Position(ts.Pos()).Line})
+			}
+		}
+	}
+	return out, nil
+}
+
+// receiverName returns the base type name of a method receiver, without
+// the pointer or type parameters.
+func receiverName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return "?"
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package treego
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMaxSymlinkDepth is the number of chained symlinks followed when
+// Options.MaxSymlinkDepth is zero, matching the Linux kernel's limit.
+const DefaultMaxSymlinkDepth = 40
+
+// errTooManyLinks is returned by resolveLink when a chain exceeds its limit.
+var errTooManyLinks = errors.New("too many levels of symbolic links")
+
+// Link notes set on Node.LinkError while following symlinks.
+const (
+	linkTooDeep = "too many links"
+	linkVisited = "already visited"
+)
+
+// resolveLink follows path hop by hop until it is no longer a symlink and
+// returns the final target. More than max hops yields errTooManyLinks.
+func resolveLink(path string, max int) (string, error) {
+	for hops := 0; ; hops++ {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return path, nil
+		}
+		if hops == max {
+			return "", errTooManyLinks
+		}
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = target
+	}
+}
+
+// followLink resolves the symlink at path. It reports whether the link
+// leads to a directory, and a note for Node.LinkError when the link is not
+// descended into: a chain longer than the depth limit, or a directory
+// already reached through another link (which also breaks link cycles).
+// Broken links are reported as plain entries.
+func (b *builder) followLink(path string) (isDir bool, note string) {
+	max := b.opts.MaxSymlinkDepth
+	if max <= 0 {
+		max = DefaultMaxSymlinkDepth
+	}
+	target, err := resolveLink(path, max)
+	if errors.Is(err, errTooManyLinks) {
+		return false, linkTooDeep
+	}
+	if err != nil {
+		return false, ""
+	}
+	info, err := os.Stat(target)
+	if err != nil || !info.IsDir() {
+		return false, ""
+	}
+	real, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		real = target
+	}
+	if _, seen := b.linkDirs.LoadOrStore(real, true); seen {
+		return true, linkVisited
+	}
+	return true, ""
+}
+
+// BrokenLink is a symlink whose target cannot be reached.
+type BrokenLink struct {
+	Path   string
+	Target string // as stored in the link, possibly relative
+}
+
+// FindBrokenLinks checks every non-directory entry under root with Lstat
+// and returns the symlinks whose target does not exist (or that loop), in
+// tree order. The links are checked concurrently.
+func FindBrokenLinks(root *Node) []BrokenLink {
+	var files []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.IsDir {
+			files = append(files, n)
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	found := make([]*BrokenLink, len(files))
+	parallelEach(len(files), func(i int) {
+		path := files[i].Path
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return
+		}
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		target, _ := os.Readlink(path)
+		found[i] = &BrokenLink{Path: path, Target: target}
+	})
+	var out []BrokenLink
+	for _, l := range found {
+		if l != nil {
+			out = append(out, *l)
+		}
+	}
+	return out
+}
+
+// PrintBrokenLinks writes one "path -> target" line per link, or a note
+// when there are none.
+func PrintBrokenLinks(w io.Writer, links []BrokenLink) error {
+	if len(links) == 0 {
+		_, err := fmt.Fprintln(w, "No broken links")
+		return err
+	}
+	ew := &errWriter{w: w}
+	for _, l := range links {
+		ew.printf("%s -> %s\n", l.Path, l.Target)
+	}
+	return ew.err
+}
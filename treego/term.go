@@ -0,0 +1,267 @@
+package treego
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorMode selects when visual features (colors, dim guides, icons and
+// hyperlinks) are used.
+type ColorMode int
+
+const (
+	// ColorAuto enables visual features only when writing to a terminal.
+	ColorAuto ColorMode = iota
+	// ColorAlways enables visual features regardless of the writer.
+	ColorAlways
+	// ColorNever always produces plain output.
+	ColorNever
+	// ColorForce is ColorAlways without the regular-file exception: escape
+	// codes are written even into files.
+	ColorForce
+)
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "", "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	default:
+		return ColorAuto, fmt.Errorf("invalid color mode %q (want auto, always or never)", s)
+	}
+}
+
+// asFile returns the *os.File behind w, looking through wrappers such as
+// LimitWriter that expose an Unwrap method.
+func asFile(w io.Writer) (*os.File, bool) {
+	for {
+		switch v := w.(type) {
+		case *os.File:
+			return v, true
+		case interface{ Unwrap() io.Writer }:
+			w = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// IsTerminal reports whether w is a character device such as a TTY.
+// Buffers, pipes and regular files are not terminals.
+func IsTerminal(w io.Writer) bool {
+	f, ok := asFile(w)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalHeight returns the number of rows of the terminal behind w, or
+// 0 when w is not a terminal or its size is unknown.
+func TerminalHeight(w io.Writer) int {
+	f, ok := asFile(w)
+	if !ok {
+		return 0
+	}
+	_, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// isRegularFile reports whether w is an *os.File backed by a regular file
+// (as opposed to a terminal, pipe or device).
+func isRegularFile(w io.Writer) bool {
+	f, ok := asFile(w)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
+
+// ColorEnabled is the single decision point for every visual feature.
+// In auto mode output is plain unless w is a terminal, NO_COLOR is unset
+// and TERM is not "dumb". ColorAlways still yields plain output when w is a
+// regular file, since files rarely want escape codes; ColorForce overrides
+// that.
+func ColorEnabled(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorForce:
+		return true
+	case ColorAlways:
+		return !isRegularFile(w)
+	case ColorNever:
+		return false
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// ResolveColor returns the fixed mode, ColorForce or ColorNever, that mode
+// amounts to when writing to w (see ColorEnabled), so that output drawn
+// into a buffer first keeps the decision made for its real destination.
+func ResolveColor(w io.Writer, mode ColorMode) ColorMode {
+	if ColorEnabled(w, mode) {
+		return ColorForce
+	}
+	return ColorNever
+}
+
+// fileURL returns a file:// URL for path, made absolute when possible.
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive paths: file:///C:/...
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiDir     = "\x1b[1;34m"
+	ansiGreen   = "\x1b[32m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiDimGray = "\x1b[90m"
+	// ansiZebra is the subtle dark-gray background of zebra stripes.
+	ansiZebra = "\x1b[48;5;236m"
+	// ansiClearLine fills the rest of the line with the current background.
+	ansiClearLine = "\x1b[K"
+)
+
+// style decorates tree output. The zero value is plain text.
+type style struct {
+	color bool
+	icons bool
+	links bool
+}
+
+// newStyle resolves the requested visual features against the writer.
+func newStyle(w io.Writer, opts Options) style {
+	if !ColorEnabled(w, opts.Color) {
+		return style{}
+	}
+	return style{color: true, icons: opts.Icons, links: opts.Hyperlinks}
+}
+
+// guide styles the box-drawing prefix of a line.
+func (s style) guide(text string) string {
+	if !s.color || text == "" {
+		return text
+	}
+	return ansiDim + text + ansiReset
+}
+
+// name styles an entry name, optionally with an icon and an OSC 8
+// hyperlink to the file.
+func (s style) name(node *Node, label string) string {
+	if s.color && node.IsDir {
+		label = ansiDir + label + ansiReset
+	}
+	if s.links && node.Path != "" {
+		label = "\x1b]8;;" + fileURL(node.Path) + "\x1b\\" + label + "\x1b]8;;\x1b\\"
+	}
+	if s.icons {
+		icon := "📄 "
+		if node.IsDir {
+			icon = "📁 "
+		}
+		label = icon + label
+	}
+	return label
+}
+
+// change styles the annotation for a diff entry.
+func (s style) change(kind ChangeKind) string {
+	marker := kind.marker()
+	if !s.color || marker == "" {
+		return marker
+	}
+	color := ansiYellow
+	switch kind {
+	case Added:
+		color = ansiGreen
+	case Removed:
+		color = ansiRed
+	case Renamed:
+		color = ansiMagenta
+	}
+	return color + marker + ansiReset
+}
+
+// maxFileSize returns the size of the largest file under node.
+func maxFileSize(node *Node) int64 {
+	if !node.IsDir {
+		return node.Size
+	}
+	var max int64
+	for _, c := range node.Children {
+		if m := maxFileSize(c); m > max {
+			max = m
+		}
+	}
+	return max
+}
+
+// heatFraction places size on a logarithmic 0..1 scale up to max, so a
+// few huge files do not flatten everything else to green.
+func heatFraction(size, max int64) float64 {
+	if size <= 0 || max <= 0 {
+		return 0
+	}
+	return math.Log1p(float64(size)) / math.Log1p(float64(max))
+}
+
+// heatColor returns a 24-bit foreground color from green (0) through
+// yellow to red (1).
+func heatColor(f float64) string {
+	f = math.Max(0, math.Min(1, f))
+	r, g := 255, 255
+	if f < 0.5 {
+		r = int(510 * f)
+	} else {
+		g = int(510 * (1 - f))
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;0m", r, g)
+}
+
+// heatLegend renders the gradient between its size endpoints.
+func heatLegend(max int64, base int64) string {
+	const cells = 10
+	var b strings.Builder
+	b.WriteString(HumanizeSize(0, base) + " ")
+	for i := 0; i < cells; i++ {
+		b.WriteString(heatColor(float64(i)/(cells-1)) + "█")
+	}
+	b.WriteString(ansiReset + " " + HumanizeSize(max, base))
+	return b.String()
+}
@@ -0,0 +1,316 @@
+package treego
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseWhere compiles a filter expression evaluated per node, such as
+//
+//	size > 1MB && ext == go
+//	isdir && name ~ "test*" || !(mtime < 2024-01-01)
+//
+// Fields are size (bytes), name, ext (without the dot, case-insensitive),
+// isdir and mtime. Comparisons use ==, !=, <, <=, > and >=; ~ matches name
+// or ext against a glob. Sizes accept B, K/KiB, M/MiB, G/GiB, T/TiB
+// (1024-based) and KB, MB, GB, TB (1000-based) suffixes; times are dates
+// (2006-01-02) or RFC 3339 timestamps. Conditions combine with &&, || and
+// !, grouped by parentheses; a bare isdir is true for directories. Values
+// with spaces or operator characters can be quoted with ' or ".
+func ParseWhere(expr string) (func(n *Node) bool, error) {
+	toks, err := lexWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &whereParser{toks: toks}
+	pred, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("where: unexpected %q", t.text)
+	}
+	return pred, nil
+}
+
+// Where keeps the entries under root that satisfy pred, plus the
+// directories leading to them (see PruneTree). The root itself is not
+// tested. It returns nil when nothing matches.
+func Where(root *Node, pred func(n *Node) bool) *Node {
+	return PruneTree(root, func(n *Node) bool {
+		return n != root && pred(n)
+	})
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokOp
+)
+
+type whereTok struct {
+	kind tokKind
+	text string
+}
+
+// whereOps lists the operators, two-character ones first.
+var whereOps = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "~", "(", ")"}
+
+func lexWhere(s string) ([]whereTok, error) {
+	var toks []whereTok
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("where: unterminated string at offset %d", i)
+			}
+			toks = append(toks, whereTok{tokString, s[i+1 : i+1+end]})
+			i += end + 2
+			continue
+		}
+		matched := false
+		for _, op := range whereOps {
+			if strings.HasPrefix(s[i:], op) {
+				toks = append(toks, whereTok{tokOp, op})
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		start := i
+		for i < len(s) && !strings.ContainsRune(" \t\n\"'&|=!<>~()", rune(s[i])) {
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("where: unexpected %q at offset %d", s[i], i)
+		}
+		toks = append(toks, whereTok{tokWord, s[start:i]})
+	}
+	return append(toks, whereTok{kind: tokEOF}), nil
+}
+
+type whereParser struct {
+	toks []whereTok
+	pos  int
+}
+
+func (p *whereParser) peek() whereTok { return p.toks[p.pos] }
+
+func (p *whereParser) next() whereTok {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the operator op if it is next.
+func (p *whereParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *whereParser) or() (func(*Node) bool, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n *Node) bool { return l(n) || right(n) }
+	}
+	return left, nil
+}
+
+func (p *whereParser) and() (func(*Node) bool, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n *Node) bool { return l(n) && right(n) }
+	}
+	return left, nil
+}
+
+func (p *whereParser) unary() (func(*Node) bool, error) {
+	if p.accept("!") {
+		inner, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n *Node) bool { return !inner(n) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("where: missing )")
+		}
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *whereParser) comparison() (func(*Node) bool, error) {
+	field := p.next()
+	if field.kind != tokWord {
+		if field.kind == tokEOF {
+			return nil, fmt.Errorf("where: unexpected end of expression")
+		}
+		return nil, fmt.Errorf("where: expected a field, got %q", field.text)
+	}
+	name := strings.ToLower(field.text)
+
+	op := p.peek()
+	if op.kind != tokOp || !isComparison(op.text) {
+		if name == "isdir" {
+			return func(n *Node) bool { return n.IsDir }, nil
+		}
+		return nil, fmt.Errorf("where: expected a comparison after %q", field.text)
+	}
+	p.next()
+	val := p.next()
+	if val.kind != tokWord && val.kind != tokString {
+		return nil, fmt.Errorf("where: expected a value after %s %s", field.text, op.text)
+	}
+
+	switch name {
+	case "size":
+		want, err := ParseSize(val.text)
+		if err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		cmp, err := orderedOp(op.text)
+		if err != nil {
+			return nil, err
+		}
+		return func(n *Node) bool { return cmp(compareInt(n.Size, want)) }, nil
+	case "mtime":
+		want, err := parseWhereTime(val.text)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := orderedOp(op.text)
+		if err != nil {
+			return nil, err
+		}
+		return func(n *Node) bool { return cmp(n.ModTime.Compare(want)) }, nil
+	case "name":
+		return stringOp(op.text, val.text, func(n *Node) string { return n.Name })
+	case "ext":
+		want := strings.ToLower(strings.TrimPrefix(val.text, "."))
+		return stringOp(op.text, want, func(n *Node) string {
+			if n.IsDir {
+				return ""
+			}
+			return strings.ToLower(strings.TrimPrefix(filepath.Ext(n.Name), "."))
+		})
+	case "isdir":
+		want, err := strconv.ParseBool(val.text)
+		if err != nil {
+			return nil, fmt.Errorf("where: isdir needs true or false, got %q", val.text)
+		}
+		switch op.text {
+		case "==":
+			return func(n *Node) bool { return n.IsDir == want }, nil
+		case "!=":
+			return func(n *Node) bool { return n.IsDir != want }, nil
+		}
+		return nil, fmt.Errorf("where: isdir supports only == and !=")
+	default:
+		return nil, fmt.Errorf("where: unknown field %q (want size, name, ext, isdir or mtime)", field.text)
+	}
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "~":
+		return true
+	}
+	return false
+}
+
+// orderedOp turns op into a test on a three-way comparison result.
+func orderedOp(op string) (func(c int) bool, error) {
+	switch op {
+	case "==":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	}
+	return nil, fmt.Errorf("where: %s cannot be used with size or mtime", op)
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func stringOp(op, want string, get func(n *Node) string) (func(*Node) bool, error) {
+	switch op {
+	case "==":
+		return func(n *Node) bool { return get(n) == want }, nil
+	case "!=":
+		return func(n *Node) bool { return get(n) != want }, nil
+	case "~":
+		if _, err := filepath.Match(want, ""); err != nil {
+			return nil, fmt.Errorf("where: invalid glob %q: %v", want, err)
+		}
+		return func(n *Node) bool {
+			ok, _ := filepath.Match(want, get(n))
+			return ok
+		}, nil
+	}
+	return nil, fmt.Errorf("where: %s cannot be used with name or ext", op)
+}
+
+func parseWhereTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("where: invalid time %q (want 2006-01-02 or RFC 3339)", s)
+}